@@ -2,5 +2,5 @@ package jlrickert
 
 import "embed"
 
-//go:embed all:content/** all:themes/** data.yaml example.html
+//go:embed all:content/** all:themes/** data.yaml example.html hugo.yaml
 var Assets embed.FS
@@ -0,0 +1,65 @@
+// Command linkcheck verifies the internal links and anchors in the
+// content tree, and optionally external links. It exits non-zero when
+// any link is broken.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/jlrickert/jlrickert.me/portfolio"
+)
+
+func main() {
+	// Parse command-line flags
+	site := flag.String("site", ".", "Site directory containing content/ and static/")
+	content := flag.String("content", "content", "Content directory relative to the site")
+	config := flag.String("config", "hugo.yaml", "Hugo config relative to the site")
+	external := flag.Bool("external", false, "Also check external links")
+	timeout := flag.Duration("timeout", 10*time.Second, "Timeout for each external request")
+	asJSON := flag.Bool("json", false, "Write the report as JSON")
+	flag.Parse()
+
+	fsys := os.DirFS(*site)
+	checker := &portfolio.LinkChecker{FS: fsys, Root: *content}
+
+	siteConfig, err := portfolio.LoadSiteConfig(fsys, *config)
+	if err != nil {
+		fail(err)
+	}
+	checker.BaseURL = siteConfig.BaseURL
+	checker.Permalinks = siteConfig.Permalinks
+
+	if *external {
+		checker.Fetcher = portfolio.HTTPFetcher{
+			Client: &http.Client{Timeout: *timeout},
+		}
+	}
+
+	report, err := checker.Check(context.Background())
+	if err != nil {
+		fail(err)
+	}
+
+	if *asJSON {
+		err = report.WriteJSON(os.Stdout)
+	} else {
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		fail(err)
+	}
+
+	if report.HasBroken() {
+		os.Exit(1)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "linkcheck:", err)
+	os.Exit(2)
+}
@@ -0,0 +1,39 @@
+// Command lint checks the content tree for front matter and structure
+// problems. It exits non-zero when any error is found.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jlrickert/jlrickert.me/portfolio"
+)
+
+func main() {
+	// Parse command-line flags
+	site := flag.String("site", ".", "Site directory containing content/ and static/")
+	content := flag.String("content", "content", "Content directory relative to the site")
+	asJSON := flag.Bool("json", false, "Write the report as JSON")
+	flag.Parse()
+
+	report, err := portfolio.Lint(os.DirFS(*site), *content)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "lint:", err)
+		os.Exit(2)
+	}
+
+	if *asJSON {
+		err = report.WriteJSON(os.Stdout)
+	} else {
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "lint:", err)
+		os.Exit(2)
+	}
+
+	if report.HasErrors() {
+		os.Exit(1)
+	}
+}
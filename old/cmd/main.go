@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -16,6 +17,13 @@ func main() {
 	// Parse command-line flags
 	addr := flag.String("addr", ":8080", "HTTP server address")
 	theme := flag.String("theme", "green-nebula-terminal", "Default theme name")
+	baseURL := flag.String("base-url", "", "Public site URL (defaults to baseURL in hugo.yaml)")
+	preview := flag.Bool("preview", false, "Serve drafts, future and expired pages")
+	previewKey := flag.String("preview-key", os.Getenv("PREVIEW_KEY"), "Key used to sign preview links")
+	previewRevocations := flag.String("preview-revocations", os.Getenv("PREVIEW_REVOCATIONS"), "File keeping revoked preview links across restarts")
+	adminToken := flag.String("admin-token", os.Getenv("ADMIN_TOKEN"), "Bearer token for admin endpoints")
+	siteDir := flag.String("site-dir", "", "Serve content from this directory instead of the embedded assets")
+	trustedProxies := flag.String("trusted-proxies", os.Getenv("TRUSTED_PROXIES"), "Comma separated addresses or CIDR ranges of proxies trusted for X-Forwarded-Proto")
 	flag.Parse()
 
 	// Create logger
@@ -26,12 +34,23 @@ func main() {
 
 	// Create server config
 	config := portfolio.ServerConfig{
-		Addr:           *addr,
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
-		IdleTimeout:    120 * time.Second,
-		MaxHeaderBytes: 1 << 20, // 1 MB
-		Theme:          *theme,
+		Addr:               *addr,
+		ReadTimeout:        10 * time.Second,
+		WriteTimeout:       10 * time.Second,
+		IdleTimeout:        120 * time.Second,
+		MaxHeaderBytes:     1 << 20, // 1 MB
+		Theme:              *theme,
+		BaseURL:            *baseURL,
+		Preview:            *preview,
+		PreviewKey:         *previewKey,
+		PreviewRevocations: *previewRevocations,
+		AdminToken:         *adminToken,
+		SiteDir:            *siteDir,
+	}
+	for proxy := range strings.SplitSeq(*trustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			config.TrustedProxies = append(config.TrustedProxies, proxy)
+		}
 	}
 
 	// Create and start server
@@ -0,0 +1,203 @@
+package portfolio
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// KindAdmonition is the node kind of Admonition.
+var KindAdmonition = ast.NewNodeKind("Admonition")
+
+// Admonition is a GitHub style alert, a blockquote starting with a
+// marker such as [!NOTE] or [!WARNING]. The first child is its
+// AdmonitionTitle.
+type Admonition struct {
+	ast.BaseBlock
+	// AdmonitionType is the lowercase type, such as "note".
+	AdmonitionType string
+}
+
+// Kind implements ast.Node.
+func (n *Admonition) Kind() ast.NodeKind {
+	return KindAdmonition
+}
+
+// Dump implements ast.Node.
+func (n *Admonition) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Type": n.AdmonitionType}, nil)
+}
+
+// KindAdmonitionTitle is the node kind of AdmonitionTitle.
+var KindAdmonitionTitle = ast.NewNodeKind("AdmonitionTitle")
+
+// AdmonitionTitle holds the text after the marker, as in
+// "> [!TIP] Try this". Without text the type is used as the title.
+type AdmonitionTitle struct {
+	ast.BaseBlock
+}
+
+// Kind implements ast.Node.
+func (n *AdmonitionTitle) Kind() ast.NodeKind {
+	return KindAdmonitionTitle
+}
+
+// Dump implements ast.Node.
+func (n *AdmonitionTitle) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// admonitionMarker matches the marker line. Like Hugo, any type is
+// accepted and a trailing + or - for foldable alerts is ignored.
+var admonitionMarker = regexp.MustCompile(`^\[!([A-Za-z]+)\][+-]?`)
+
+// admonitionTransformer turns blockquotes that start with a marker into
+// admonitions.
+type admonitionTransformer struct{}
+
+func (admonitionTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	var quotes []*ast.Blockquote
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if quote, ok := n.(*ast.Blockquote); ok && entering {
+			quotes = append(quotes, quote)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, quote := range quotes {
+		para, ok := quote.FirstChild().(*ast.Paragraph)
+		if !ok || para.Lines().Len() == 0 {
+			continue
+		}
+		first := para.Lines().At(0)
+		match := admonitionMarker.FindSubmatchIndex(first.Value(source))
+		if match == nil {
+			continue
+		}
+		markerEnd := first.Start + match[1]
+
+		admonition := &Admonition{
+			AdmonitionType: strings.ToLower(string(first.Value(source)[match[2]:match[3]])),
+		}
+		title := &AdmonitionTitle{}
+		admonition.AppendChild(admonition, title)
+
+		// Move the inlines on the marker line, less the marker, into
+		// the title.
+		for child := para.FirstChild(); child != nil; {
+			next := child.NextSibling()
+			start, stop, ok := inlineSpan(child)
+			if !ok || start >= first.Stop {
+				break
+			}
+			para.RemoveChild(para, child)
+			if stop > markerEnd {
+				if t, isText := child.(*ast.Text); isText {
+					if t.Segment.Start < markerEnd {
+						t.Segment = t.Segment.WithStart(markerEnd)
+					}
+					t.SetSoftLineBreak(false)
+					t.SetHardLineBreak(false)
+				}
+				title.AppendChild(title, child)
+			}
+			child = next
+		}
+		trimTitle(title, source)
+
+		if para.ChildCount() == 0 {
+			quote.RemoveChild(quote, para)
+		}
+		for child := quote.FirstChild(); child != nil; {
+			next := child.NextSibling()
+			admonition.AppendChild(admonition, child)
+			child = next
+		}
+		quote.Parent().ReplaceChild(quote.Parent(), quote, admonition)
+	}
+}
+
+// inlineSpan returns the source span of the text in an inline node.
+func inlineSpan(n ast.Node) (start, stop int, ok bool) {
+	start = -1
+	ast.Walk(n, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if t, isText := n.(*ast.Text); isText && entering {
+			if start < 0 {
+				start = t.Segment.Start
+			}
+			stop = t.Segment.Stop
+		}
+		return ast.WalkContinue, nil
+	})
+	return start, stop, start >= 0
+}
+
+// trimTitle drops the space between the marker and the title.
+func trimTitle(title *AdmonitionTitle, source []byte) {
+	for child := title.FirstChild(); child != nil; child = title.FirstChild() {
+		t, ok := child.(*ast.Text)
+		if !ok {
+			return
+		}
+		t.Segment = t.Segment.TrimLeftSpace(source)
+		if !t.Segment.IsEmpty() {
+			return
+		}
+		title.RemoveChild(title, t)
+	}
+}
+
+type admonitionRenderer struct{}
+
+func (admonitionRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindAdmonition, renderAdmonition)
+	reg.Register(KindAdmonitionTitle, renderAdmonitionTitle)
+}
+
+// renderAdmonition matches the blockquote render hook of the Hugo
+// theme so both render alerts the same way.
+func renderAdmonition(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		w.WriteString(`<div class="admonition admonition-`)
+		w.WriteString(html.EscapeString(node.(*Admonition).AdmonitionType))
+		w.WriteString("\">\n")
+	} else {
+		w.WriteString("</div>\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func renderAdmonitionTitle(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		w.WriteString("</p>\n")
+		return ast.WalkContinue, nil
+	}
+	w.WriteString(`<p class="admonition-title">`)
+	if !node.HasChildren() {
+		kind := node.Parent().(*Admonition).AdmonitionType
+		w.WriteString(html.EscapeString(strings.ToUpper(kind[:1]) + kind[1:]))
+	}
+	return ast.WalkContinue, nil
+}
+
+type admonitionExtension struct{}
+
+// admonitions renders GitHub style alerts such as > [!NOTE].
+var admonitions goldmark.Extender = admonitionExtension{}
+
+func (admonitionExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(admonitionTransformer{}, 100),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(admonitionRenderer{}, 500),
+	))
+}
@@ -0,0 +1,85 @@
+package portfolio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownAdmonitions(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "note",
+			src:  joinLines("> [!NOTE]", "> Useful information."),
+			want: joinLines(
+				`<div class="admonition admonition-note">`,
+				`<p class="admonition-title">Note</p>`,
+				`<p>Useful information.</p>`,
+				`</div>`,
+			),
+		},
+		{
+			name: "custom title with markup",
+			src:  joinLines("> [!WARNING] Mind the *gap*", ">", "> Second paragraph."),
+			want: joinLines(
+				`<div class="admonition admonition-warning">`,
+				`<p class="admonition-title">Mind the <em>gap</em></p>`,
+				`<p>Second paragraph.</p>`,
+				`</div>`,
+			),
+		},
+		{
+			name: "foldable",
+			src:  joinLines("> [!tip]-", "> Try `go vet`."),
+			want: joinLines(
+				`<div class="admonition admonition-tip">`,
+				`<p class="admonition-title">Tip</p>`,
+				`<p>Try <code>go vet</code>.</p>`,
+				`</div>`,
+			),
+		},
+		{
+			name: "plain blockquote",
+			src:  joinLines("> [link](/x) and [!NOTE] later"),
+			want: joinLines(
+				`<blockquote>`,
+				`<p><a href="/x">link</a> and [!NOTE] later</p>`,
+				`</blockquote>`,
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, renderMarkdown(t, tt.src))
+		})
+	}
+}
+
+func TestMarkdownMermaid(t *testing.T) {
+	out := renderMarkdown(t, joinLines("```mermaid", "graph TD", "  A --> B & C", "```"))
+	assert.Equal(t, "<pre class=\"mermaid\">\n  graph TD\n  A --&gt; B &amp; C\n</pre>\n", out)
+
+	page := &Page{Content: []byte(out)}
+	assert.True(t, page.HasMermaid())
+	page = &Page{Content: []byte(renderMarkdown(t, joinLines("```go", "x := 1", "```")))}
+	assert.False(t, page.HasMermaid())
+}
+
+func TestMarkdownFootnotesAndDefinitions(t *testing.T) {
+	out := renderMarkdown(t, joinLines(
+		"A claim.[^1]",
+		"",
+		"Term",
+		": Definition",
+		"",
+		"[^1]: The source.",
+	))
+	assert.Contains(t, out, `<sup id="fnref:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a></sup>`)
+	assert.Contains(t, out, `<div class="footnotes" role="doc-endnotes">`)
+	assert.Contains(t, out, "<dl>\n<dt>Term</dt>\n<dd>Definition</dd>\n</dl>")
+}
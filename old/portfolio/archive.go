@@ -0,0 +1,213 @@
+package portfolio
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// monthLayout formats the month keys used by the archive and stats.
+const monthLayout = "2006-01"
+
+// Archive groups posts by year and month, newest first.
+type Archive struct {
+	Total int           `json:"total"`
+	Years []ArchiveYear `json:"years"`
+}
+
+// ArchiveYear is a year of posts.
+type ArchiveYear struct {
+	Year   int            `json:"year"`
+	Count  int            `json:"count"`
+	Months []ArchiveMonth `json:"months"`
+}
+
+// ArchiveMonth is a month of posts, newest first.
+type ArchiveMonth struct {
+	// Key is the month as YYYY-MM.
+	Key   string        `json:"key"`
+	Month time.Month    `json:"month"`
+	Name  string        `json:"name"`
+	Count int           `json:"count"`
+	Posts []PostSummary `json:"posts"`
+}
+
+// NewArchive groups pages by publication month. Undated pages are left
+// out.
+func NewArchive(pages []*Page) Archive {
+	dated := datedPages(pages)
+	sort.SliceStable(dated, func(i, j int) bool {
+		return dated[i].Date().After(dated[j].Date())
+	})
+
+	archive := Archive{Total: len(dated), Years: []ArchiveYear{}}
+	for _, page := range dated {
+		date := page.Date()
+		if n := len(archive.Years); n == 0 || archive.Years[n-1].Year != date.Year() {
+			archive.Years = append(archive.Years, ArchiveYear{Year: date.Year()})
+		}
+		year := &archive.Years[len(archive.Years)-1]
+		year.Count++
+
+		if n := len(year.Months); n == 0 || year.Months[n-1].Month != date.Month() {
+			year.Months = append(year.Months, ArchiveMonth{
+				Key:   date.Format(monthLayout),
+				Month: date.Month(),
+				Name:  date.Month().String(),
+			})
+		}
+		month := &year.Months[len(year.Months)-1]
+		month.Count++
+		month.Posts = append(month.Posts, summarizePost(page))
+	}
+	return archive
+}
+
+// WritingStats summarizes writing cadence across posts.
+type WritingStats struct {
+	Posts              int             `json:"posts"`
+	Words              int             `json:"words"`
+	AverageReadingTime float64         `json:"averageReadingTime"`
+	Months             []MonthActivity `json:"months"`
+	Tags               []TagActivity   `json:"tags"`
+	Longest            *PostStat       `json:"longest,omitempty"`
+	Shortest           *PostStat       `json:"shortest,omitempty"`
+}
+
+// MonthActivity is the writing done in a month. Months without posts
+// between the first and last post are included so gaps show up.
+type MonthActivity struct {
+	Key   string `json:"key"`
+	Posts int    `json:"posts"`
+	Words int    `json:"words"`
+}
+
+// TagActivity is the number of posts with a tag in each month.
+type TagActivity struct {
+	Tag    string         `json:"tag"`
+	Total  int            `json:"total"`
+	Months map[string]int `json:"months"`
+}
+
+// PostStat is a post with its word count.
+type PostStat struct {
+	PostSummary
+	WordCount int `json:"wordCount"`
+}
+
+// NewWritingStats computes writing statistics over dated pages, folding
+// tags through aliases.
+func NewWritingStats(pages []*Page, aliases map[string]string) WritingStats {
+	dated := datedPages(pages)
+	sort.SliceStable(dated, func(i, j int) bool {
+		return dated[i].Date().Before(dated[j].Date())
+	})
+
+	stats := WritingStats{
+		Posts:  len(dated),
+		Months: []MonthActivity{},
+		Tags:   []TagActivity{},
+	}
+	if len(dated) == 0 {
+		return stats
+	}
+
+	// Month keys follow each post's own time zone, so the range is taken
+	// from the keys rather than the instants.
+	firstKey, lastKey := dated[0].Date().Format(monthLayout), ""
+	for _, page := range dated {
+		key := page.Date().Format(monthLayout)
+		firstKey, lastKey = min(firstKey, key), max(lastKey, key)
+	}
+	start, _ := time.Parse(monthLayout, firstKey)
+	for m := start; m.Format(monthLayout) <= lastKey; m = m.AddDate(0, 1, 0) {
+		stats.Months = append(stats.Months, MonthActivity{Key: m.Format(monthLayout)})
+	}
+	months := make(map[string]*MonthActivity, len(stats.Months))
+	for i := range stats.Months {
+		months[stats.Months[i].Key] = &stats.Months[i]
+	}
+
+	tags := make(map[string]*TagActivity)
+	var longest, shortest *Page
+	readingTime := 0
+	for _, page := range dated {
+		key := page.Date().Format(monthLayout)
+		words := page.WordCount()
+		months[key].Posts++
+		months[key].Words += words
+		stats.Words += words
+		readingTime += page.ReadingTime()
+
+		if longest == nil || words > longest.WordCount() {
+			longest = page
+		}
+		if shortest == nil || words < shortest.WordCount() {
+			shortest = page
+		}
+
+		// Spellings that normalize to the same tag count once per post.
+		seen := make(map[string]bool)
+		for _, tag := range page.Tags() {
+			name := NormalizeTerm(tag, aliases)
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			activity := tags[name]
+			if activity == nil {
+				activity = &TagActivity{Tag: name, Months: make(map[string]int)}
+				tags[name] = activity
+			}
+			activity.Total++
+			activity.Months[key]++
+		}
+	}
+
+	stats.AverageReadingTime = float64(readingTime) / float64(len(dated))
+	stats.Longest = &PostStat{summarizePost(longest), longest.WordCount()}
+	stats.Shortest = &PostStat{summarizePost(shortest), shortest.WordCount()}
+
+	for _, activity := range tags {
+		stats.Tags = append(stats.Tags, *activity)
+	}
+	sort.Slice(stats.Tags, func(i, j int) bool {
+		if stats.Tags[i].Total != stats.Tags[j].Total {
+			return stats.Tags[i].Total > stats.Tags[j].Total
+		}
+		return stats.Tags[i].Tag < stats.Tags[j].Tag
+	})
+	return stats
+}
+
+func datedPages(pages []*Page) []*Page {
+	var dated []*Page
+	for _, page := range pages {
+		if page.Kind == KindPage && !page.Front().Date.IsZero() {
+			dated = append(dated, page)
+		}
+	}
+	return dated
+}
+
+// Archive returns the published blog posts grouped by year and month.
+func (m *AssetManager) Archive(ctx context.Context) (Archive, error) {
+	pages, err := m.ListSection(ctx, "blog")
+	if err != nil {
+		return Archive{}, err
+	}
+	return NewArchive(pages), nil
+}
+
+// WritingStats returns writing statistics over the published blog posts.
+func (m *AssetManager) WritingStats(ctx context.Context) (WritingStats, error) {
+	pages, err := m.ListSection(ctx, "blog")
+	if err != nil {
+		return WritingStats{}, err
+	}
+	site, err := m.Site()
+	if err != nil {
+		return WritingStats{}, err
+	}
+	return NewWritingStats(pages, site.TermAliases()), nil
+}
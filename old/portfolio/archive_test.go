@@ -0,0 +1,119 @@
+package portfolio
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func archiveFixture() []*Page {
+	words := func(n int) string { return strings.Repeat("word ", n) }
+	return []*Page{
+		testPage("blog/a.md", "---\ntitle: A\ndate: 2024-11-05\ntags: [go]\n---\n"+words(100)),
+		testPage("blog/b.md", "---\ntitle: B\ndate: 2025-01-10\ntags: [go, golang, notes]\n---\n"+words(450)),
+		testPage("blog/c.md", "---\ntitle: C\ndate: 2025-01-20\ntags: [notes]\n---\n"+words(20)),
+		testPage("blog/d.md", "---\ntitle: D\ndate: 2025-03-01\n---\n"+words(230)),
+		testPage("blog/undated.md", "---\ntitle: Undated\n---\n"+words(5)),
+	}
+}
+
+func TestNewArchive(t *testing.T) {
+	archive := NewArchive(archiveFixture())
+
+	assert.Equal(t, 4, archive.Total)
+	require.Len(t, archive.Years, 2)
+
+	year := archive.Years[0]
+	assert.Equal(t, 2025, year.Year)
+	assert.Equal(t, 3, year.Count)
+	require.Len(t, year.Months, 2)
+	assert.Equal(t, "2025-03", year.Months[0].Key)
+	assert.Equal(t, "January", year.Months[1].Name)
+	assert.Equal(t, 2, year.Months[1].Count)
+	assert.Equal(t, "C", year.Months[1].Posts[0].Title)
+
+	assert.Equal(t, 2024, archive.Years[1].Year)
+	assert.Equal(t, 1, archive.Years[1].Count)
+
+	assert.Empty(t, NewArchive(nil).Years)
+}
+
+func TestNewWritingStats(t *testing.T) {
+	stats := NewWritingStats(archiveFixture(), DefaultTermAliases)
+
+	assert.Equal(t, 4, stats.Posts)
+	assert.Equal(t, 800, stats.Words)
+	// 1 + 3 + 1 + 2 minutes.
+	assert.InDelta(t, 1.75, stats.AverageReadingTime, 1e-9)
+
+	// Months without posts are kept so gaps in cadence show.
+	assert.Equal(t, []MonthActivity{
+		{Key: "2024-11", Posts: 1, Words: 100},
+		{Key: "2024-12"},
+		{Key: "2025-01", Posts: 2, Words: 470},
+		{Key: "2025-02"},
+		{Key: "2025-03", Posts: 1, Words: 230},
+	}, stats.Months)
+
+	// "golang" is an alias of "go" and counts once for post B.
+	assert.Equal(t, []TagActivity{
+		{Tag: "go", Total: 2, Months: map[string]int{"2024-11": 1, "2025-01": 1}},
+		{Tag: "notes", Total: 2, Months: map[string]int{"2025-01": 2}},
+	}, stats.Tags)
+
+	require.NotNil(t, stats.Longest)
+	assert.Equal(t, "B", stats.Longest.Title)
+	assert.Equal(t, 450, stats.Longest.WordCount)
+	require.NotNil(t, stats.Shortest)
+	assert.Equal(t, "C", stats.Shortest.Title)
+
+	empty := NewWritingStats(nil, DefaultTermAliases)
+	assert.Zero(t, empty.Posts)
+	assert.Nil(t, empty.Longest)
+}
+
+func TestWritingStatsSiteAliases(t *testing.T) {
+	manager := &AssetManager{
+		Assets: fstest.MapFS{
+			"hugo.yaml":            {Data: []byte("title: Test\nparams:\n  termAliases:\n    k8s: kubernetes\n")},
+			"content/blog/pods.md": {Data: []byte("---\ntitle: Pods\ndate: 2025-01-10\ntags: [k8s, kubernetes]\n---\nPods.\n")},
+			"content/blog/helm.md": {Data: []byte("---\ntitle: Helm\ndate: 2025-02-10\ntags: [kubernetes]\n---\nHelm.\n")},
+		},
+		Now: time.Now,
+	}
+
+	stats, err := manager.WritingStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []TagActivity{
+		{Tag: "kubernetes", Total: 2, Months: map[string]int{"2025-01": 1, "2025-02": 1}},
+	}, stats.Tags)
+}
+
+func TestHandleArchive(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), nil)
+
+	for _, path := range []string{"/api/archive", "/archive?format=json", "/api/stats"} {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, path)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"), path)
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body), path)
+	}
+
+	req := httptest.NewRequest("GET", "/archive", nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `<section class="archive">`)
+}
@@ -1,80 +1,178 @@
 package portfolio
 
 import (
-	"bytes"
 	"context"
-	"embed"
 	"fmt"
 	"html/template"
 	"io/fs"
 	"log/slog"
 	"maps"
+	"path"
 	"path/filepath"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/jlrickert/jlrickert.me"
-	"gopkg.in/yaml.v3"
 )
 
 const DefaultTheme = "green-nebula-terminal"
 
 // AssetManager manages embedded assets including posts and data files.
 type AssetManager struct {
-	Assets embed.FS
+	// Assets holds the site: content, data, themes and hugo.yaml.
+	Assets fs.FS
 	Logger *slog.Logger
+	// Theme names the theme under themes/ whose layouts/_shortcodes
+	// templates are used alongside the built-in shortcodes.
+	Theme string
+
+	// Publish controls which drafts, future and expired pages are
+	// served. The zero value follows Hugo's defaults.
+	Publish PublishOptions
+	// Now returns the current time used for publication rules.
+	Now func() time.Time
+	// Related configures the related pages precomputed with the index.
+	Related RelatedOptions
+
+	mu           sync.Mutex
+	index        *ContentIndex
+	site         *SiteConfig
+	taxonomies   Taxonomies
+	search       *SearchIndex
+	searchSource *ContentIndex
 }
 
 // NewAssetManager creates and returns a new AssetManager instance.
 func NewAssetManager(theme string) *AssetManager {
-	return &AssetManager{Assets: jlrickert.Assets}
+	return &AssetManager{Assets: jlrickert.Assets, Theme: theme, Now: time.Now}
 }
 
-// GetPage retrieves and parses a post by slug, converting its markdown
-// content to HTML.
-func (m *AssetManager) GetPage(ctx context.Context, path string) (*Page, error) {
-	fp := filepath.Join("content", path)
+// Index returns the content index, building it on first use.
+func (m *AssetManager) Index(ctx context.Context) (*ContentIndex, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	data, err := fs.ReadFile(m.Assets, fp)
-	if err != nil {
-		return nil, fmt.Errorf(
-			"slug \"%s\" does not exist: %w",
-			path,
-			err,
-		)
+	if m.index != nil {
+		return m.index, nil
 	}
-
-	ext := filepath.Ext(fp)
-
-	switch ext {
-	case "md":
-		meta, content, err := m.parseFrontmatter(data)
+	shortcodes := BuiltinShortcodes()
+	if m.Theme != "" {
+		var err error
+		shortcodes, err = LoadShortcodes(m.Assets, path.Join("themes", m.Theme, "layouts", "_shortcodes"))
 		if err != nil {
 			return nil, err
 		}
-		var buf bytes.Buffer
-		if err := Markdown.Convert(content, &buf); err != nil {
-			return nil, err
+	}
+	idx, err := NewContentIndexWithShortcodes(ctx, m.Assets, "content", shortcodes)
+	if err != nil {
+		return nil, err
+	}
+	if m.Logger != nil {
+		for _, file := range slices.Sorted(maps.Keys(idx.Skipped())) {
+			m.Logger.Warn("skipping content with invalid front matter", "file", file, "error", idx.Skipped()[file])
 		}
+	}
+	site, err := m.loadSite()
+	if err != nil {
+		return nil, err
+	}
+	idx.SetPermalinks(site.Permalinks)
+	idx.BuildRelated(site.TermAliases(), m.Related)
+	m.index = idx
+	m.taxonomies = m.buildTaxonomies(idx, site)
+	return idx, nil
+}
+
+// Reload drops the cached content index and site config so they are
+// read again from Assets on next use. The search index is left as is;
+// SyncSearch updates it with only the documents that changed.
+func (m *AssetManager) Reload() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.index = nil
+	m.site = nil
+	m.taxonomies = nil
+}
+
+// Site returns the Hugo site config, loading it on first use.
+func (m *AssetManager) Site() (*SiteConfig, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.loadSite()
+}
+
+func (m *AssetManager) loadSite() (*SiteConfig, error) {
+	if m.site != nil {
+		return m.site, nil
+	}
+	site, err := LoadSiteConfig(m.Assets, "hugo.yaml")
+	if err != nil {
+		return nil, err
+	}
+	m.site = site
+	return site, nil
+}
+
+// GetPage retrieves a page by content path or slug. Markdown content is
+// already converted to HTML.
+func (m *AssetManager) GetPage(ctx context.Context, path string) (*Page, error) {
+	idx, err := m.Index(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page := idx.Lookup(path)
+	if page == nil || !m.Publish.Visible(page, m.now()) {
+		return nil, fmt.Errorf("slug \"%s\" does not exist", path)
+	}
+	return page, nil
+}
+
+// GetPreviewPage retrieves a page by content path or slug regardless of
+// its publication state.
+func (m *AssetManager) GetPreviewPage(ctx context.Context, path string) (*Page, error) {
+	idx, err := m.Index(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-		return &Page{
-			Path:    path,
-			Type:    "markdown",
-			Content: buf.Bytes(),
-			Meta:    meta,
-		}, nil
-	case "html":
-		return &Page{
-			Path:    path,
-			Type:    "html",
-			Content: data,
-			Meta:    map[string]any{},
-		}, nil
-	}
-	return nil, fmt.Errorf("%s is unsupported", path)
+	page := idx.Lookup(path)
+	if page == nil {
+		return nil, fmt.Errorf("slug \"%s\" does not exist", path)
+	}
+	return page, nil
+}
+
+// ListSection returns the visible regular pages of a content section
+// such as "blog" or "projects".
+func (m *AssetManager) ListSection(ctx context.Context, section string) ([]*Page, error) {
+	idx, err := m.Index(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list section %q: %w", section, err)
+	}
+	return m.Publish.Filter(idx.Section(section), m.now()), nil
+}
+
+// AllPages returns every indexed page regardless of publication state.
+func (m *AssetManager) AllPages(ctx context.Context) ([]*Page, error) {
+	idx, err := m.Index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return idx.Pages(), nil
+}
+
+func (m *AssetManager) now() time.Time {
+	if m.Now != nil {
+		return m.Now()
+	}
+	return time.Now()
 }
 
 // GetData retrieves and parses the data.yaml file into a Data struct.
 func (m *AssetManager) GetData(ctx context.Context) (*Data, error) {
-	data, err := m.Assets.ReadFile(filepath.Join("data", "data.yaml"))
+	data, err := fs.ReadFile(m.Assets, filepath.Join("data", "data.yaml"))
 	if err != nil {
 		return nil, fmt.Errorf(
 			"failed to read data.yaml: %w",
@@ -87,72 +185,24 @@ func (m *AssetManager) GetData(ctx context.Context) (*Data, error) {
 
 // GetTemplateContent retrieves raw template file contents without parsing
 func (m *AssetManager) GetTemplateContent(theme, name string) ([]byte, error) {
-	path := fmt.Sprintf("themes/%s/default/%s.html", theme, name)
-	content, err := m.Assets.ReadFile(path)
+	path := fmt.Sprintf("themes/%s/templates/%s.html", theme, name)
+	content, err := fs.ReadFile(m.Assets, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read template file %s: %w", path, err)
 	}
 	return content, nil
 }
 
+// GetTemplate parses a single template of theme, falling back to the
+// default theme when the theme does not have it. The server renders
+// through the cached ThemeManager instead.
 func (m *AssetManager) GetTemplate(theme, name string) (*template.Template, error) {
-	path := fmt.Sprintf("themes/%s/default/%s.html", theme, name)
-	content, err := m.Assets.ReadFile(path)
-	if err != nil {
-		// Fallback to default theme
-		path = fmt.Sprintf("themes/%s/default/%s.html", theme, name)
-		content, err = m.Assets.ReadFile(path)
+	content, err := m.GetTemplateContent(theme, name)
+	if err != nil && theme != DefaultTheme {
+		content, err = m.GetTemplateContent(DefaultTheme, name)
 	}
-
-	tmpl, err := template.New(name).Funcs(TemplateFuncs()).Parse(string(content))
 	if err != nil {
-		m.Logger.Error("failed to parse base template", "template", "_base", "error", err)
 		return nil, err
 	}
-
-	return tmpl, err
-}
-
-// parseFrontmatter extracts YAML frontmatter from markdown content.
-//
-// It expects frontmatter to be delimited by --- at the start and
-// separated from content by \n---\n. Returns metadata map, remaining
-// content, and any parsing errors.
-func (m *AssetManager) parseFrontmatter(data []byte) (
-	map[string]any,
-	[]byte,
-	error,
-) {
-	meta := make(map[string]any)
-	content := data
-
-	if !bytes.HasPrefix(data, []byte("---")) {
-		return meta, content, nil
-	}
-
-	parts := bytes.SplitN(data, []byte("\n---\n"), 2)
-	if len(parts) < 2 {
-		return meta, content, nil
-	}
-
-	frontmatterStr := string(bytes.TrimPrefix(
-		parts[0],
-		[]byte("---\n"),
-	))
-	content = parts[1]
-
-	yamlMap := make(map[string]any)
-	if err := yaml.Unmarshal(
-		[]byte(frontmatterStr),
-		yamlMap,
-	); err != nil {
-		return meta, content, fmt.Errorf(
-			"failed to parse frontmatter: %w",
-			err,
-		)
-	}
-
-	maps.Copy(meta, yamlMap)
-
-	return meta, content, nil
+	return template.New(name).Funcs(TemplateFuncs()).Parse(string(content))
 }
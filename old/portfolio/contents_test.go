@@ -21,7 +21,7 @@ func TestGetPost(t *testing.T) {
 
 	require.NoError(t, err)
 	assert.NotNil(t, post)
-	assert.Equal(t, "first-post", post.Path)
+	assert.Equal(t, "blog/first-post.md", post.Path)
 }
 
 func TestGetPostNotFound(t *testing.T) {
@@ -206,3 +206,27 @@ func TestGetDataSocialLinks(t *testing.T) {
 	assert.Contains(t, data.LinkedIn, "linkedin.com")
 	assert.Contains(t, data.Portfolio, "jlrickert.me")
 }
+
+func TestGetPageHidesDrafts(t *testing.T) {
+	manager := NewAssetManager(DefaultTheme)
+	post, err := manager.GetPage(context.Background(), "blog/coffee-cake")
+
+	assert.Error(t, err)
+	assert.Nil(t, post)
+
+	manager.Publish = PreviewOptions
+	post, err = manager.GetPage(context.Background(), "blog/coffee-cake")
+	require.NoError(t, err)
+	assert.Equal(t, "Coffee Cake", post.Title())
+}
+
+func TestListSectionHidesDrafts(t *testing.T) {
+	manager := NewAssetManager(DefaultTheme)
+	posts, err := manager.ListSection(context.Background(), "blog")
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, posts)
+	for _, post := range posts {
+		assert.False(t, post.Draft(), post.Path)
+	}
+}
@@ -34,12 +34,12 @@ type Experience struct {
 
 // Skills represents all skill categories
 type Skills struct {
-	Languages   []string `yaml:"languages"`
-	Frontend    []string `yaml:"frontend"`
-	Backend     []string `yaml:"backend"`
-	CloudDevOps []string `yaml:"cloud_devops"`
-	Databases   []string `yaml:"databases"`
-	Tools       []string `yaml:"tools"`
+	Languages   []Skill `yaml:"languages"`
+	Frontend    []Skill `yaml:"frontend"`
+	Backend     []Skill `yaml:"backend"`
+	CloudDevOps []Skill `yaml:"cloud_devops"`
+	Databases   []Skill `yaml:"databases"`
+	Tools       []Skill `yaml:"tools"`
 }
 
 // Education represents an education entry
@@ -58,6 +58,16 @@ type Certification struct {
 	CredentialID string `yaml:"credential_id"`
 }
 
+// Anchor returns the id of the experience entry on the home page.
+func (e Experience) Anchor() string {
+	return "experience-" + urlize(e.Company+" "+e.Title)
+}
+
+// Anchor returns the id of the certification entry on the home page.
+func (c Certification) Anchor() string {
+	return "certification-" + urlize(c.Name)
+}
+
 // LoadData unmarshals the provided YAML bytes into a Data struct
 func LoadData(content []byte) (*Data, error) {
 	var data Data
@@ -0,0 +1,398 @@
+package portfolio
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// DefaultFeedLimit is how many of the newest pages a feed holds.
+const DefaultFeedLimit = 20
+
+// FeedFormat is a syndication format.
+type FeedFormat string
+
+// Supported feed formats.
+const (
+	FeedRSS  FeedFormat = "rss"
+	FeedAtom FeedFormat = "atom"
+	FeedJSON FeedFormat = "json"
+)
+
+// FeedFormats lists the supported formats.
+var FeedFormats = []FeedFormat{FeedRSS, FeedAtom, FeedJSON}
+
+// File returns the file name a feed of the format is served under.
+func (f FeedFormat) File() string {
+	switch f {
+	case FeedAtom:
+		return "atom.xml"
+	case FeedJSON:
+		return "feed.json"
+	}
+	return "index.xml"
+}
+
+// ContentType returns the Content-Type header value of the format.
+func (f FeedFormat) ContentType() string {
+	return f.mediaType() + "; charset=utf-8"
+}
+
+// Feed is a syndication feed independent of its format.
+type Feed struct {
+	Title       string
+	Description string
+	Language    string
+	Author      string
+	// Link is the absolute URL of the HTML page the feed follows.
+	Link string
+	// Self is the absolute URL of the feed itself.
+	Self    string
+	Updated time.Time
+	Items   []FeedItem
+}
+
+// FeedItem is a feed entry. URLs are absolute and Content is the full
+// rendered HTML with absolute links.
+type FeedItem struct {
+	ID        string
+	Title     string
+	URL       string
+	Summary   string
+	Content   string
+	Author    string
+	Published time.Time
+	Updated   time.Time
+	Tags      []string
+}
+
+// NewFeed builds a feed of the newest limit pages. Pages are expected
+// to be filtered for publication already. Relative URLs are resolved
+// against baseURL.
+func NewFeed(pages []*Page, baseURL string, limit int) Feed {
+	if limit <= 0 {
+		limit = DefaultFeedLimit
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		base = &url.URL{}
+	}
+
+	pages = datedPages(pages)
+	sort.SliceStable(pages, func(i, j int) bool {
+		return pages[i].Date().After(pages[j].Date())
+	})
+	if len(pages) > limit {
+		pages = pages[:limit]
+	}
+
+	var feed Feed
+	for _, page := range pages {
+		front := page.Front()
+		link := resolveURL(base, page.URL())
+		updated := front.Lastmod
+		if updated.Before(front.Date) {
+			updated = front.Date
+		}
+		if updated.After(feed.Updated) {
+			feed.Updated = updated
+		}
+		feed.Items = append(feed.Items, FeedItem{
+			ID:        link,
+			Title:     page.Title(),
+			URL:       link,
+			Summary:   page.Description(),
+			Content:   absoluteLinks(string(page.Content), parseURL(link)),
+			Author:    front.Author,
+			Published: front.Date,
+			Updated:   updated,
+			Tags:      page.Tags(),
+		})
+	}
+	return feed
+}
+
+// Write writes the feed in format.
+func (f Feed) Write(w io.Writer, format FeedFormat) error {
+	switch format {
+	case FeedRSS:
+		return f.WriteRSS(w)
+	case FeedAtom:
+		return f.WriteAtom(w)
+	case FeedJSON:
+		return f.WriteJSON(w)
+	}
+	return fmt.Errorf("unknown feed format %q", format)
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Content string     `xml:"xmlns:content,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Self          atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Description string   `xml:"description,omitempty"`
+	Content     string   `xml:"content:encoded"`
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// WriteRSS writes the feed as RSS 2.0 with the full content in
+// content:encoded.
+func (f Feed) WriteRSS(w io.Writer) error {
+	doc := rssFeed{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Content: "http://purl.org/rss/1.0/modules/content/",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        f.Link,
+			Description: f.Description,
+			Language:    f.Language,
+			Self:        atomLink{Href: f.Self, Rel: "self", Type: FeedRSS.mediaType()},
+		},
+	}
+	if !f.Updated.IsZero() {
+		doc.Channel.LastBuildDate = f.Updated.Format(time.RFC1123Z)
+	}
+	for _, item := range f.Items {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.URL,
+			GUID:        rssGUID{IsPermaLink: item.ID == item.URL, Value: item.ID},
+			PubDate:     item.Published.Format(time.RFC1123Z),
+			Description: item.Summary,
+			Content:     item.Content,
+			Categories:  item.Tags,
+		})
+	}
+	return writeXML(w, doc)
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string      `xml:"title"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Links    []atomLink  `xml:"link"`
+	Author   *atomAuthor `xml:"author,omitempty"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Author     *atomAuthor    `xml:"author,omitempty"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    atomText       `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+}
+
+// WriteAtom writes the feed as Atom 1.0.
+func (f Feed) WriteAtom(w io.Writer) error {
+	doc := atomFeed{
+		Title:    f.Title,
+		ID:       f.Link,
+		Updated:  f.Updated.UTC().Format(time.RFC3339),
+		Subtitle: f.Description,
+		Links: []atomLink{
+			{Href: f.Self, Rel: "self", Type: FeedAtom.mediaType()},
+			{Href: f.Link, Rel: "alternate", Type: "text/html"},
+		},
+	}
+	if f.Author != "" {
+		doc.Author = &atomAuthor{Name: f.Author}
+	}
+	for _, item := range f.Items {
+		entry := atomEntry{
+			Title:     item.Title,
+			ID:        item.ID,
+			Link:      atomLink{Href: item.URL, Rel: "alternate", Type: "text/html"},
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Updated:   item.Updated.UTC().Format(time.RFC3339),
+			Content:   atomText{Type: "html", Value: item.Content},
+		}
+		if item.Author != "" {
+			entry.Author = &atomAuthor{Name: item.Author}
+		}
+		if item.Summary != "" {
+			entry.Summary = &atomText{Type: "text", Value: item.Summary}
+		}
+		for _, tag := range item.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return writeXML(w, doc)
+}
+
+type jsonFeed struct {
+	Version     string           `json:"version"`
+	Title       string           `json:"title"`
+	HomePageURL string           `json:"home_page_url,omitempty"`
+	FeedURL     string           `json:"feed_url,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Language    string           `json:"language,omitempty"`
+	Authors     []jsonFeedAuthor `json:"authors,omitempty"`
+	Items       []jsonFeedItem   `json:"items"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	Title         string           `json:"title"`
+	ContentHTML   string           `json:"content_html"`
+	Summary       string           `json:"summary,omitempty"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified,omitempty"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+}
+
+// WriteJSON writes the feed as JSON Feed 1.1.
+func (f Feed) WriteJSON(w io.Writer) error {
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     f.Self,
+		Description: f.Description,
+		Language:    f.Language,
+		Items:       []jsonFeedItem{},
+	}
+	if f.Author != "" {
+		doc.Authors = []jsonFeedAuthor{{Name: f.Author}}
+	}
+	for _, item := range f.Items {
+		entry := jsonFeedItem{
+			ID:            item.ID,
+			URL:           item.URL,
+			Title:         item.Title,
+			ContentHTML:   item.Content,
+			Summary:       item.Summary,
+			DatePublished: item.Published.Format(time.RFC3339),
+			Tags:          item.Tags,
+		}
+		if !item.Updated.Equal(item.Published) {
+			entry.DateModified = item.Updated.Format(time.RFC3339)
+		}
+		if item.Author != "" {
+			entry.Authors = []jsonFeedAuthor{{Name: item.Author}}
+		}
+		doc.Items = append(doc.Items, entry)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// mediaType returns the content type without parameters.
+func (f FeedFormat) mediaType() string {
+	switch f {
+	case FeedAtom:
+		return "application/atom+xml"
+	case FeedJSON:
+		return "application/feed+json"
+	}
+	return "application/rss+xml"
+}
+
+func writeXML(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// htmlURLAttr matches URL valued attributes in rendered HTML.
+var htmlURLAttr = regexp.MustCompile(`(?i)(\s(?:href|src|poster)\s*=\s*)"([^"]*)"`)
+
+// absoluteLinks rewrites the relative URLs in content against base so
+// the HTML works outside the site, as feed readers need.
+func absoluteLinks(content string, base *url.URL) string {
+	if base == nil || base.Host == "" {
+		return content
+	}
+	return htmlURLAttr.ReplaceAllStringFunc(content, func(attr string) string {
+		m := htmlURLAttr.FindStringSubmatch(attr)
+		return m[1] + `"` + resolveURL(base, m[2]) + `"`
+	})
+}
+
+// resolveURL resolves ref against base, keeping ref when it is already
+// absolute or cannot be parsed.
+func resolveURL(base *url.URL, ref string) string {
+	u, err := url.Parse(ref)
+	if err != nil || u.IsAbs() || base.Host == "" {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
+
+// parseURL parses raw, returning nil when it is not a valid URL.
+func parseURL(raw string) *url.URL {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil
+	}
+	return u
+}
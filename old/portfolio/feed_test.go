@@ -0,0 +1,190 @@
+package portfolio
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func feedFixture() []*Page {
+	return []*Page{
+		testPage("blog/old.md", "---\ntitle: Old\ndate: 2024-01-01\n---\nOld post\n"),
+		testPage("blog/new.md", joinLines(
+			"---",
+			"title: New",
+			"date: 2025-02-01",
+			"lastmod: 2025-03-01",
+			"description: Fresh",
+			"author: Jared",
+			"tags: [go]",
+			"---",
+			"See [keg](/blog/keg/), [the image](diagram.png), [a section](#usage),",
+			"[mail](mailto:me@example.com) and [elsewhere](https://go.dev/).",
+			"",
+			"![diagram](/static/diagram.png)",
+		)),
+		testPage("blog/undated.md", "---\ntitle: Undated\n---\nNo date\n"),
+	}
+}
+
+func TestNewFeed(t *testing.T) {
+	feed := NewFeed(feedFixture(), "https://example.com", 0)
+
+	require.Len(t, feed.Items, 2)
+	item := feed.Items[0]
+	assert.Equal(t, "New", item.Title)
+	assert.Equal(t, "https://example.com/blog/new/", item.URL)
+	assert.Equal(t, item.URL, item.ID)
+	assert.Equal(t, "Fresh", item.Summary)
+	assert.Equal(t, "Jared", item.Author)
+	assert.Equal(t, []string{"go"}, item.Tags)
+	assert.True(t, item.Updated.After(item.Published))
+	assert.Equal(t, item.Updated, feed.Updated)
+
+	// Relative links resolve against the page URL, absolute ones are
+	// left alone.
+	assert.Contains(t, item.Content, `href="https://example.com/blog/keg/"`)
+	assert.Contains(t, item.Content, `href="https://example.com/blog/new/diagram.png"`)
+	assert.Contains(t, item.Content, `href="https://example.com/blog/new/#usage"`)
+	assert.Contains(t, item.Content, `href="mailto:me@example.com"`)
+	assert.Contains(t, item.Content, `href="https://go.dev/"`)
+	assert.Contains(t, item.Content, `src="https://example.com/static/diagram.png"`)
+
+	assert.Len(t, NewFeed(feedFixture(), "https://example.com", 1).Items, 1)
+
+	// Without a base URL links stay relative.
+	relative := NewFeed(feedFixture(), "", 0)
+	assert.Equal(t, "/blog/new/", relative.Items[0].URL)
+	assert.Contains(t, relative.Items[0].Content, `href="/blog/keg/"`)
+}
+
+func TestFeedFormats(t *testing.T) {
+	feed := NewFeed(feedFixture(), "https://example.com", 0)
+	feed.Title = "Blog"
+	feed.Link = "https://example.com/blog/"
+	feed.Self = "https://example.com/blog/index.xml"
+
+	var rss struct {
+		Channel struct {
+			Title string `xml:"title"`
+			Items []struct {
+				GUID    string `xml:"guid"`
+				PubDate string `xml:"pubDate"`
+				Content string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	var buf bytes.Buffer
+	require.NoError(t, feed.Write(&buf, FeedRSS))
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &rss))
+	assert.Equal(t, "Blog", rss.Channel.Title)
+	require.Len(t, rss.Channel.Items, 2)
+	assert.Equal(t, "https://example.com/blog/new/", rss.Channel.Items[0].GUID)
+	_, err := time.Parse(time.RFC1123Z, rss.Channel.Items[0].PubDate)
+	assert.NoError(t, err)
+	assert.Contains(t, rss.Channel.Items[0].Content, "https://example.com/blog/keg/")
+
+	var atom struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		ID      string   `xml:"id"`
+		Updated string   `xml:"updated"`
+		Entries []struct {
+			Content struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+	buf.Reset()
+	require.NoError(t, feed.Write(&buf, FeedAtom))
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &atom))
+	assert.Equal(t, "https://example.com/blog/", atom.ID)
+	assert.Equal(t, "2025-03-01T00:00:00Z", atom.Updated)
+	require.Len(t, atom.Entries, 2)
+	assert.Equal(t, "html", atom.Entries[0].Content.Type)
+	assert.Contains(t, atom.Entries[0].Content.Value, "<a href=")
+
+	var jsonFeed struct {
+		Version string `json:"version"`
+		FeedURL string `json:"feed_url"`
+		Items   []struct {
+			ContentHTML  string `json:"content_html"`
+			DateModified string `json:"date_modified"`
+		} `json:"items"`
+	}
+	buf.Reset()
+	require.NoError(t, feed.Write(&buf, FeedJSON))
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &jsonFeed))
+	assert.Equal(t, "https://jsonfeed.org/version/1.1", jsonFeed.Version)
+	require.Len(t, jsonFeed.Items, 2)
+	assert.NotEmpty(t, jsonFeed.Items[0].DateModified)
+	assert.Empty(t, jsonFeed.Items[1].DateModified)
+
+	assert.Error(t, feed.Write(&buf, FeedFormat("yaml")))
+}
+
+func TestHandleFeed(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), nil)
+
+	for _, format := range FeedFormats {
+		req := httptest.NewRequest("GET", "/blog/"+format.File(), nil)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, format)
+		assert.Equal(t, format.ContentType(), w.Header().Get("Content-Type"))
+		assert.NotContains(t, w.Body.String(), `"/blog/`, "links are absolute")
+
+		etag := w.Header().Get("ETag")
+		require.NotEmpty(t, etag)
+
+		req = httptest.NewRequest("GET", "/blog/"+format.File(), nil)
+		req.Header.Set("If-None-Match", etag)
+		w = httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotModified, w.Code, format)
+	}
+
+	req := httptest.NewRequest("GET", "/blog/index.xml", nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	modified := w.Header().Get("Last-Modified")
+	require.NotEmpty(t, modified)
+
+	req = httptest.NewRequest("GET", "/blog/index.xml", nil)
+	req.Header.Set("If-Modified-Since", modified)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	req = httptest.NewRequest("GET", "/tags/php/feed.json", nil)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var tagFeed struct {
+		Title   string `json:"title"`
+		FeedURL string `json:"feed_url"`
+		Items   []struct {
+			Tags []string `json:"tags"`
+		} `json:"items"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&tagFeed))
+	assert.Contains(t, tagFeed.Title, "php")
+	assert.True(t, strings.HasSuffix(tagFeed.FeedURL, "/tags/php/feed.json"))
+	require.NotEmpty(t, tagFeed.Items)
+	for _, item := range tagFeed.Items {
+		assert.Contains(t, item.Tags, "php")
+	}
+
+	req = httptest.NewRequest("GET", "/tags/nope/index.xml", nil)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,453 @@
+package portfolio
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// FrontMatterFormat is one of the front matter formats Hugo supports.
+type FrontMatterFormat string
+
+const (
+	FormatNone FrontMatterFormat = ""
+	FormatYAML FrontMatterFormat = "yaml"
+	FormatTOML FrontMatterFormat = "toml"
+	FormatJSON FrontMatterFormat = "json"
+)
+
+// GalleryImage is an entry of the gallery front matter list.
+type GalleryImage struct {
+	File string `json:"file"`
+	Alt  string `json:"alt,omitempty"`
+}
+
+// FrontMatter is the typed front matter of a content file. Keys without
+// a typed field are kept in Params.
+type FrontMatter struct {
+	Format FrontMatterFormat `json:"-"`
+
+	Title       string         `json:"title,omitempty"`
+	Slug        string         `json:"slug,omitempty"`
+	Date        time.Time      `json:"date,omitzero"`
+	Lastmod     time.Time      `json:"lastmod,omitzero"`
+	Draft       bool           `json:"draft,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Author      string         `json:"author,omitempty"`
+	Image       string         `json:"image,omitempty"`
+	Gallery     []GalleryImage `json:"gallery,omitempty"`
+	Link        string         `json:"link,omitempty"`
+	Params      map[string]any `json:"params,omitempty"`
+}
+
+// FrontMatterError reports a front matter problem at a line of the
+// content file. Line is 1 based and 0 when unknown.
+type FrontMatterError struct {
+	Line int
+	Key  string
+	Err  error
+}
+
+func (e *FrontMatterError) Error() string {
+	var b strings.Builder
+	b.WriteString("front matter")
+	if e.Line > 0 {
+		fmt.Fprintf(&b, " line %d", e.Line)
+	}
+	if e.Key != "" {
+		fmt.Fprintf(&b, " %q", e.Key)
+	}
+	b.WriteString(": ")
+	b.WriteString(e.Err.Error())
+	return b.String()
+}
+
+func (e *FrontMatterError) Unwrap() error {
+	return e.Err
+}
+
+// ParseFrontMatter splits a content file into its front matter and body.
+//
+// YAML (---), TOML (+++) and JSON ({...}) front matter is recognized
+// with either LF or CRLF line endings. Files without front matter are
+// returned unchanged with an empty FrontMatter. The raw map of every key
+// is returned alongside the typed struct.
+func ParseFrontMatter(data []byte) (FrontMatter, map[string]any, []byte, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	raw := make(map[string]any)
+
+	var (
+		format FrontMatterFormat
+		block  []byte
+		body   []byte
+		err    error
+	)
+	switch {
+	case hasDelimiter(data, "---"):
+		format = FormatYAML
+		block, body, err = splitDelimited(data, "---")
+	case hasDelimiter(data, "+++"):
+		format = FormatTOML
+		block, body, err = splitDelimited(data, "+++")
+	case bytes.HasPrefix(data, []byte("{")):
+		format = FormatJSON
+		block, body, err = splitJSON(data)
+	default:
+		return FrontMatter{}, raw, data, nil
+	}
+	if err != nil {
+		return FrontMatter{}, raw, data, err
+	}
+
+	// Line numbers in errors are relative to the file. YAML and TOML
+	// blocks start after the opening delimiter line.
+	offset := 1
+	if format == FormatJSON {
+		offset = 0
+	}
+
+	switch format {
+	case FormatYAML:
+		err = yaml.Unmarshal(block, &raw)
+		if err == nil {
+			localizeYAMLDates(block, raw)
+		}
+	case FormatTOML:
+		err = toml.Unmarshal(block, &raw)
+	case FormatJSON:
+		err = json.Unmarshal(block, &raw)
+	}
+	if err != nil {
+		return FrontMatter{}, raw, body, &FrontMatterError{
+			Line: decodeErrorLine(err, block) + offset,
+			Err:  err,
+		}
+	}
+	if raw == nil {
+		raw = make(map[string]any)
+	}
+
+	fm, err := decodeFrontMatter(raw, func(key string) int {
+		if line := keyLine(block, key); line > 0 {
+			return line + offset
+		}
+		return 0
+	})
+	fm.Format = format
+	return fm, raw, body, err
+}
+
+// localizeYAMLDates makes the top level bare dates of a YAML block, such
+// as "date: 2025-01-06", midnight in the local time zone as Hugo does.
+// YAML decodes them as UTC midnight, which once decoded cannot be told
+// apart from an explicit "2025-01-06T00:00:00Z", so the source scalars
+// are checked.
+func localizeYAMLDates(block []byte, raw map[string]any) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(block, &doc); err != nil || len(doc.Content) == 0 {
+		return
+	}
+	mapping := doc.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		if value.Kind != yaml.ScalarNode || value.ShortTag() != "!!timestamp" {
+			continue
+		}
+		if _, err := time.Parse(time.DateOnly, value.Value); err != nil {
+			continue
+		}
+		if t, ok := raw[key.Value].(time.Time); ok {
+			raw[key.Value] = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+		}
+	}
+}
+
+// DecodeFrontMatter converts a raw front matter map into a FrontMatter.
+// Unknown keys are copied into Params.
+func DecodeFrontMatter(raw map[string]any) (FrontMatter, error) {
+	return decodeFrontMatter(raw, nil)
+}
+
+func decodeFrontMatter(raw map[string]any, lineOf func(key string) int) (FrontMatter, error) {
+	fm := FrontMatter{Params: make(map[string]any)}
+	var errs []error
+	fail := func(key string, err error) {
+		fmErr := &FrontMatterError{Key: key, Err: err}
+		if lineOf != nil {
+			fmErr.Line = lineOf(key)
+		}
+		errs = append(errs, fmErr)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(raw)) {
+		value := raw[key]
+		var err error
+		switch strings.ToLower(key) {
+		case "title":
+			fm.Title, err = asString(value)
+		case "slug":
+			fm.Slug, err = asString(value)
+		case "description":
+			fm.Description, err = asString(value)
+		case "author":
+			fm.Author, err = asString(value)
+		case "image":
+			fm.Image, err = asString(value)
+		case "link":
+			fm.Link, err = asString(value)
+		case "date":
+			fm.Date, err = asTime(value)
+		case "lastmod":
+			fm.Lastmod, err = asTime(value)
+		case "draft":
+			fm.Draft, err = asBool(value)
+		case "tags":
+			fm.Tags, err = asStrings(value)
+		case "gallery":
+			fm.Gallery, err = asGallery(value)
+		default:
+			fm.Params[key] = value
+		}
+		if err != nil {
+			fail(key, err)
+		}
+	}
+
+	return fm, errors.Join(errs...)
+}
+
+// hasDelimiter reports whether data opens with a delimiter line.
+func hasDelimiter(data []byte, delim string) bool {
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+	return string(bytes.TrimRight(line, " \t\r")) == delim
+}
+
+// splitDelimited returns the block between the opening and closing
+// delimiter lines and the body after the closing line.
+func splitDelimited(data []byte, delim string) ([]byte, []byte, error) {
+	_, rest, _ := bytes.Cut(data, []byte("\n"))
+	start := len(data) - len(rest)
+
+	for pos := start; pos < len(data); {
+		end := bytes.IndexByte(data[pos:], '\n')
+		next := len(data)
+		if end >= 0 {
+			next = pos + end + 1
+		}
+		if string(bytes.TrimRight(data[pos:next], " \t\r\n")) == delim {
+			return data[start:pos], data[next:], nil
+		}
+		pos = next
+	}
+
+	return nil, nil, &FrontMatterError{
+		Line: 1,
+		Err:  fmt.Errorf("missing closing %q delimiter", delim),
+	}
+}
+
+// splitJSON returns the leading JSON object and the body after it.
+func splitJSON(data []byte) ([]byte, []byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var obj json.RawMessage
+	if err := dec.Decode(&obj); err != nil {
+		return nil, nil, &FrontMatterError{
+			Line: decodeErrorLine(err, data),
+			Err:  err,
+		}
+	}
+	body := data[dec.InputOffset():]
+	body = bytes.TrimPrefix(bytes.TrimPrefix(body, []byte("\r")), []byte("\n"))
+	return obj, body, nil
+}
+
+var yamlLine = regexp.MustCompile(`line (\d+)`)
+
+// decodeErrorLine extracts the 1 based line within block from a YAML,
+// TOML or JSON decoding error.
+func decodeErrorLine(err error, block []byte) int {
+	var tomlErr *toml.DecodeError
+	if errors.As(err, &tomlErr) {
+		row, _ := tomlErr.Position()
+		return row
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return offsetLine(block, syntaxErr.Offset)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return offsetLine(block, typeErr.Offset)
+	}
+	if m := yamlLine.FindStringSubmatch(err.Error()); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+	return 0
+}
+
+// offsetLine converts a byte offset to a 1 based line number.
+func offsetLine(block []byte, offset int64) int {
+	offset = min(max(offset, 0), int64(len(block)))
+	return bytes.Count(block[:offset], []byte("\n")) + 1
+}
+
+// keyLine finds the line of a top level key within a front matter block.
+func keyLine(block []byte, key string) int {
+	pattern := regexp.MustCompile(`^\s*"?` + regexp.QuoteMeta(key) + `"?\s*[:=]`)
+	for i, line := range bytes.Split(block, []byte("\n")) {
+		if pattern.Match(line) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// parseDate parses the date formats Hugo accepts in front matter. Dates
+// without a zone are interpreted in the local time zone.
+func parseDate(value string) (time.Time, bool) {
+	for _, layout := range []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05Z07:00",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+	} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func asString(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case int, int64, float64, bool:
+		return fmt.Sprint(v), nil
+	}
+	return "", fmt.Errorf("expected a string, got %T", value)
+}
+
+func asBool(value any) (bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("expected a boolean, got %q", v)
+		}
+		return b, nil
+	}
+	return false, fmt.Errorf("expected a boolean, got %T", value)
+}
+
+func asTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case nil:
+		return time.Time{}, nil
+	case time.Time:
+		return v, nil
+	case toml.LocalDate:
+		return v.AsTime(time.Local), nil
+	case toml.LocalDateTime:
+		return v.AsTime(time.Local), nil
+	case string:
+		if v == "" {
+			return time.Time{}, nil
+		}
+		if t, ok := parseDate(v); ok {
+			return t, nil
+		}
+		return time.Time{}, fmt.Errorf("invalid date %q", v)
+	}
+	return time.Time{}, fmt.Errorf("expected a date, got %T", value)
+}
+
+func asStrings(value any) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return []string{}, nil
+	case []string:
+		return v, nil
+	case []any:
+		out := make([]string, 0, len(v))
+		for i, item := range v {
+			s, err := asString(item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	case string:
+		// A YAML flow list written as a string, e.g. "[go, web]", or a
+		// comma separated list.
+		if strings.HasPrefix(strings.TrimSpace(v), "[") {
+			var out []string
+			if err := yaml.Unmarshal([]byte(v), &out); err != nil {
+				return nil, fmt.Errorf("invalid list %q", v)
+			}
+			return out, nil
+		}
+		var out []string
+		for item := range strings.SplitSeq(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("expected a list of strings, got %T", value)
+}
+
+func asGallery(value any) ([]GalleryImage, error) {
+	items, ok := value.([]any)
+	if !ok {
+		if value == nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("expected a list, got %T", value)
+	}
+
+	gallery := make([]GalleryImage, 0, len(items))
+	for i, item := range items {
+		switch v := item.(type) {
+		case string:
+			gallery = append(gallery, GalleryImage{File: v})
+		case map[string]any:
+			file, err := asString(v["file"])
+			if err != nil {
+				return nil, fmt.Errorf("item %d file: %w", i, err)
+			}
+			alt, err := asString(v["alt"])
+			if err != nil {
+				return nil, fmt.Errorf("item %d alt: %w", i, err)
+			}
+			gallery = append(gallery, GalleryImage{File: file, Alt: alt})
+		default:
+			return nil, fmt.Errorf("item %d: expected a file or mapping, got %T", i, item)
+		}
+	}
+	return gallery, nil
+}
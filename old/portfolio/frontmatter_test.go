@@ -0,0 +1,166 @@
+package portfolio
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFrontMatterFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format FrontMatterFormat
+	}{
+		{
+			name:   "yaml",
+			input:  "---\ntitle: Hello\ndate: 2025-01-06\ndraft: true\ntags:\n  - go\n  - web\nseries: intro\n---\nBody\n",
+			format: FormatYAML,
+		},
+		{
+			name:   "yaml crlf",
+			input:  "---\r\ntitle: Hello\r\ndate: 2025-01-06\r\ndraft: true\r\ntags: [go, web]\r\nseries: intro\r\n---\r\nBody\n",
+			format: FormatYAML,
+		},
+		{
+			name:   "toml",
+			input:  "+++\ntitle = 'Hello'\ndate = 2025-01-06\ndraft = true\ntags = ['go', 'web']\nseries = 'intro'\n+++\nBody\n",
+			format: FormatTOML,
+		},
+		{
+			name:   "toml crlf",
+			input:  "+++\r\ntitle = 'Hello'\r\ndate = 2025-01-06\r\ndraft = true\r\ntags = ['go', 'web']\r\nseries = 'intro'\r\n+++\r\nBody\n",
+			format: FormatTOML,
+		},
+		{
+			name:   "json",
+			input:  "{\n  \"title\": \"Hello\",\n  \"date\": \"2025-01-06\",\n  \"draft\": true,\n  \"tags\": [\"go\", \"web\"],\n  \"series\": \"intro\"\n}\nBody\n",
+			format: FormatJSON,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm, raw, body, err := ParseFrontMatter([]byte(tt.input))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.format, fm.Format)
+			assert.Equal(t, "Hello", fm.Title)
+			assert.Equal(t, time.Date(2025, 1, 6, 0, 0, 0, 0, time.Local), fm.Date)
+			assert.True(t, fm.Draft)
+			assert.Equal(t, []string{"go", "web"}, fm.Tags)
+			assert.Equal(t, map[string]any{"series": "intro"}, fm.Params)
+			assert.Contains(t, raw, "title")
+			assert.Equal(t, "Body\n", string(body))
+		})
+	}
+}
+
+func TestParseFrontMatterExplicitUTCMidnight(t *testing.T) {
+	fm, raw, _, err := ParseFrontMatter([]byte("---\ndate: 2025-01-06T00:00:00Z\nlastmod: 2025-01-06\n---\n"))
+	require.NoError(t, err)
+
+	// Only a bare date is taken as local midnight.
+	assert.Equal(t, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), fm.Date)
+	assert.Equal(t, time.UTC, fm.Date.Location())
+	assert.Equal(t, time.Date(2025, 1, 6, 0, 0, 0, 0, time.Local), fm.Lastmod)
+	assert.Equal(t, fm.Lastmod, raw["lastmod"])
+}
+
+func TestParseFrontMatterNone(t *testing.T) {
+	fm, raw, body, err := ParseFrontMatter([]byte("# Just markdown\n"))
+	require.NoError(t, err)
+	assert.Equal(t, FormatNone, fm.Format)
+	assert.Empty(t, raw)
+	assert.Equal(t, "# Just markdown\n", string(body))
+}
+
+func TestParseFrontMatterTypedFields(t *testing.T) {
+	input := strings.Join([]string{
+		"---",
+		`date: "2025-12-04T22:52:01-06:00"`,
+		"lastmod: 2025-12-05",
+		"slug: b2mfg",
+		"author: Jared Rickert",
+		"image: b2mfg.png",
+		"link: https://b2mfg.com/",
+		"imgAlt: b2mfg website",
+		"gallery:",
+		"  - file: b2mfg.png",
+		"    alt: preview",
+		"  - other.png",
+		"---",
+		"",
+	}, "\n")
+
+	fm, _, _, err := ParseFrontMatter([]byte(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, "2025-12-05T04:52:01Z", fm.Date.UTC().Format(time.RFC3339))
+	assert.Equal(t, time.Date(2025, 12, 5, 0, 0, 0, 0, time.Local), fm.Lastmod)
+	assert.Equal(t, "b2mfg", fm.Slug)
+	assert.Equal(t, "Jared Rickert", fm.Author)
+	assert.Equal(t, "b2mfg.png", fm.Image)
+	assert.Equal(t, "https://b2mfg.com/", fm.Link)
+	assert.Equal(t, []GalleryImage{
+		{File: "b2mfg.png", Alt: "preview"},
+		{File: "other.png"},
+	}, fm.Gallery)
+	assert.Equal(t, "b2mfg website", fm.Params["imgAlt"])
+}
+
+func TestParseFrontMatterErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		line  int
+		key   string
+	}{
+		{name: "unclosed", input: "---\ntitle: x\n", line: 1},
+		{name: "yaml syntax", input: "---\ntitle: x\n  bad: indent\n---\n", line: 3},
+		{name: "yaml duplicate key", input: "---\na: 1\nb: 2\na: 3\n---\n", line: 4},
+		{name: "toml syntax", input: "+++\ntitle = 'x'\ndate = \n+++\n", line: 3},
+		{name: "json syntax", input: "{\n\"title\": \"x\",\n}\n", line: 3},
+		{name: "bad date", input: "---\ntitle: x\ndate: someday\n---\n", line: 3, key: "date"},
+		{name: "bad draft crlf", input: "---\r\ntitle: x\r\n\r\ndraft: maybe\r\n---\r\n", line: 4, key: "draft"},
+		{name: "bad tags", input: "+++\ntags = 3\n+++\n", line: 2, key: "tags"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := ParseFrontMatter([]byte(tt.input))
+			require.Error(t, err)
+
+			var fmErr *FrontMatterError
+			require.ErrorAs(t, err, &fmErr)
+			assert.Equal(t, tt.line, fmErr.Line, err.Error())
+			assert.Equal(t, tt.key, fmErr.Key)
+		})
+	}
+}
+
+func TestPageDateRFC3339(t *testing.T) {
+	page, err := loadPage("content", "blog/what-is-a-keg.md", []byte(
+		"---\ndate: \"2025-12-04T22:52:01-06:00\"\ntitle: What Is a Keg\n---\nBody\n",
+	), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2025, page.Date().Year())
+	assert.Equal(t, time.December, page.Date().Month())
+	assert.Equal(t, "What Is a Keg", page.Title())
+}
+
+func TestPageFrontFromMeta(t *testing.T) {
+	page := &Page{Meta: map[string]any{
+		"title": "From Meta",
+		"tags":  "[go, web]",
+		"draft": "true",
+	}}
+
+	assert.Equal(t, "From Meta", page.Title())
+	assert.Equal(t, []string{"go", "web"}, page.Tags())
+	assert.True(t, page.Draft())
+}
@@ -0,0 +1,117 @@
+package portfolio
+
+import (
+	"bytes"
+	"html"
+	"io"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/util"
+)
+
+// HighlightStyle is the chroma style matching the green-nebula-terminal
+// palette. Code is rendered with CSS classes so the colors live in the
+// stylesheet written by HighlightCSS.
+var HighlightStyle = chroma.MustNewStyle("green-nebula-terminal", chroma.StyleEntries{
+	chroma.Background:          "#00ff00 bg:#0a0a0a",
+	chroma.LineNumbers:         "#00aa00",
+	chroma.LineNumbersTable:    "#00aa00",
+	chroma.LineHighlight:       "bg:#003300",
+	chroma.Error:               "#ff5555",
+	chroma.Comment:             "italic #00aa00",
+	chroma.CommentPreproc:      "noitalic #66ff66",
+	chroma.Keyword:             "bold #33ff33",
+	chroma.KeywordType:         "nobold #7dffb2",
+	chroma.Operator:            "#66ff66",
+	chroma.Punctuation:         "#00cc00",
+	chroma.Name:                "#00ff00",
+	chroma.NameBuiltin:         "#7dffb2",
+	chroma.NameFunction:        "bold #b3ffb3",
+	chroma.NameClass:           "bold #b3ffb3",
+	chroma.NameTag:             "#33ff33",
+	chroma.NameAttribute:       "#7dffb2",
+	chroma.NameVariable:        "#ccffcc",
+	chroma.LiteralString:       "#ccff66",
+	chroma.LiteralStringEscape: "#ffff66",
+	chroma.LiteralNumber:       "#66ffcc",
+	chroma.GenericDeleted:      "#ff5555",
+	chroma.GenericInserted:     "#33ff33",
+	chroma.GenericHeading:      "bold #33ff33",
+	chroma.GenericSubheading:   "#00aa00",
+	chroma.GenericEmph:         "italic",
+	chroma.GenericStrong:       "bold",
+	chroma.GenericPrompt:       "#00aa00",
+})
+
+// highlightFormatOptions are shared by the renderer and the stylesheet
+// so the class names line up.
+var highlightFormatOptions = []chromahtml.Option{
+	chromahtml.WithClasses(true),
+	chromahtml.TabWidth(4),
+}
+
+// highlighter highlights fenced code blocks. Fence attributes control
+// each block:
+//
+//	```go {linenos=true hl_lines=[2,"4-6"] linenostart=10 filename="main.go"}
+//
+// linenos shows line numbers, hl_lines highlights lines relative to
+// linenostart and filename adds a caption above the block.
+var highlighter = highlighting.NewHighlighting(
+	highlighting.WithCustomStyle(HighlightStyle),
+	highlighting.WithFormatOptions(highlightFormatOptions...),
+	highlighting.WithWrapperRenderer(renderCodeBlockWrapper),
+)
+
+// HighlightCSS writes the stylesheet for highlighted code blocks.
+func HighlightCSS(w io.Writer) error {
+	return chromahtml.New(highlightFormatOptions...).WriteCSS(w, HighlightStyle)
+}
+
+// renderCodeBlockWrapper adds the filename caption around a code block
+// and the plain <pre><code> for blocks chroma does not highlight.
+func renderCodeBlockWrapper(w util.BufWriter, ctx highlighting.CodeBlockContext, entering bool) {
+	filename := codeBlockFilename(ctx)
+	if entering {
+		if filename != "" {
+			w.WriteString(`<figure class="code-block"><figcaption class="code-filename">`)
+			w.WriteString(html.EscapeString(filename))
+			w.WriteString("</figcaption>")
+		}
+		if !ctx.Highlighted() {
+			w.WriteString("<pre><code")
+			if lang, ok := ctx.Language(); ok && len(lang) > 0 {
+				w.WriteString(` class="language-`)
+				w.WriteString(html.EscapeString(string(lang)))
+				w.WriteString(`"`)
+			}
+			w.WriteString(">")
+		}
+		return
+	}
+	if !ctx.Highlighted() {
+		w.WriteString("</code></pre>\n")
+	}
+	if filename != "" {
+		w.WriteString("</figure>\n")
+	}
+}
+
+// codeBlockFilename returns the filename fence attribute, accepting
+// title as an alias.
+func codeBlockFilename(ctx highlighting.CodeBlockContext) string {
+	attrs := ctx.Attributes()
+	if attrs == nil {
+		return ""
+	}
+	for _, name := range []string{"filename", "title"} {
+		if value, ok := attrs.GetString(name); ok {
+			if b, ok := value.([]byte); ok {
+				return string(bytes.TrimSpace(b))
+			}
+		}
+	}
+	return ""
+}
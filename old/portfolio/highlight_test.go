@@ -0,0 +1,96 @@
+package portfolio
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func renderMarkdown(t *testing.T, src string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, Markdown.Convert([]byte(src), &buf))
+	return buf.String()
+}
+
+func TestHighlightCodeBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		contains []string
+		excludes []string
+		// highlighted is the number of highlighted lines.
+		highlighted int
+	}{
+		{
+			name:     "language",
+			src:      joinLines("```go", "func main() {}", "```"),
+			contains: []string{`<pre class="chroma">`, `<span class="kd">func</span>`, `<span class="nf">main</span>`},
+			excludes: []string{"style=", `class="ln"`, "<figure"},
+		},
+		{
+			name:     "line numbers",
+			src:      joinLines("```go {linenos=true linenostart=10}", "a := 1", "b := 2", "```"),
+			contains: []string{`<span class="ln">10</span>`, `<span class="ln">11</span>`},
+		},
+		{
+			name:        "highlighted lines",
+			src:         joinLines("```go {hl_lines=[1,\"3-4\"]}", "a := 1", "b := 2", "c := 3", "d := 4", "```"),
+			highlighted: 3,
+		},
+		{
+			name: "filename",
+			src:  joinLines("```go {filename=\"cmd/<main>.go\"}", "package main", "```"),
+			contains: []string{
+				`<figure class="code-block"><figcaption class="code-filename">cmd/&lt;main&gt;.go</figcaption><pre class="chroma">`,
+				"</pre></figure>",
+			},
+		},
+		{
+			name:     "unknown language",
+			src:      joinLines("```nope {title=notes.txt}", "<b>plain</b>", "```"),
+			contains: []string{`<figcaption class="code-filename">notes.txt</figcaption><pre><code class="language-nope">&lt;b&gt;plain&lt;/b&gt;`, "</code></pre>\n</figure>"},
+			excludes: []string{"chroma"},
+		},
+		{
+			name:     "no language",
+			src:      joinLines("```", "plain", "```"),
+			contains: []string{"<pre><code>plain\n</code></pre>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := renderMarkdown(t, tt.src)
+			assert.Equal(t, tt.highlighted, strings.Count(out, `class="line hl"`))
+			for _, want := range tt.contains {
+				assert.Contains(t, out, want)
+			}
+			for _, unwanted := range tt.excludes {
+				assert.NotContains(t, out, unwanted)
+			}
+		})
+	}
+}
+
+func TestHighlightCSS(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, HighlightCSS(&buf))
+	css := buf.String()
+	assert.Contains(t, css, ".chroma { color: #00ff00; background-color: #0a0a0a;")
+	assert.Contains(t, css, ".chroma .hl { background-color: #003300 }")
+	assert.Contains(t, css, ".chroma .c { color: #00aa00; font-style: italic }")
+
+	server := NewServer(DefaultServerConfig(), nil)
+	req := httptest.NewRequest("GET", "/static/css/highlight.css", nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/css; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, css, w.Body.String())
+}
@@ -0,0 +1,324 @@
+package portfolio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/yuin/goldmark/text"
+)
+
+// Page kinds, matching Hugo's .Kind values.
+const (
+	KindHome    = "home"
+	KindSection = "section"
+	KindPage    = "page"
+)
+
+// ContentIndex is an in-memory index of the Hugo content tree.
+//
+// It understands sections (top level directories), section list pages
+// (_index files), leaf bundles (a directory containing index.md whose
+// other files are resources) and plain leaf pages.
+type ContentIndex struct {
+	pages    []*Page
+	byPath   map[string]*Page
+	bySlug   map[string]*Page
+	byName   map[string][]*Page
+	sections map[string][]*Page
+	lists    map[string]*Page
+	related  *RelatedIndex
+	series   map[string]*Series
+	// skipped holds the files left out for invalid front matter.
+	skipped map[string]error
+}
+
+// NewContentIndex walks root within fsys and indexes every markdown and
+// html content file, expanding the built-in shortcodes.
+func NewContentIndex(ctx context.Context, fsys fs.FS, root string) (*ContentIndex, error) {
+	return NewContentIndexWithShortcodes(ctx, fsys, root, BuiltinShortcodes())
+}
+
+// NewContentIndexWithShortcodes is NewContentIndex with the given
+// shortcodes, such as those from LoadShortcodes.
+//
+// A file with invalid front matter is left out rather than failing the
+// whole index; see Skipped.
+func NewContentIndexWithShortcodes(ctx context.Context, fsys fs.FS, root string, shortcodes Shortcodes) (*ContentIndex, error) {
+	idx := &ContentIndex{
+		byPath:   make(map[string]*Page),
+		bySlug:   make(map[string]*Page),
+		byName:   make(map[string][]*Page),
+		sections: make(map[string][]*Page),
+		lists:    make(map[string]*Page),
+		skipped:  make(map[string]error),
+	}
+
+	// Leaf bundles are found first so their other files are treated as
+	// resources rather than pages.
+	bundles := make(map[string]bool)
+	err := fs.WalkDir(fsys, root, func(fp string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && d.Name() == "index.md" {
+			bundles[path.Dir(fp)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", root, err)
+	}
+
+	resources := make(map[string][]string)
+	err = fs.WalkDir(fsys, root, func(fp string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		dir := path.Dir(fp)
+		ext := path.Ext(fp)
+		isContent := ext == ".md" || ext == ".html"
+
+		if bundle := bundleOf(bundles, dir); bundle != "" && d.Name() != "index.md" {
+			rel := strings.TrimPrefix(fp, bundle+"/")
+			resources[bundle] = append(resources[bundle], rel)
+			return nil
+		}
+		if !isContent {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, fp)
+		if err != nil {
+			return err
+		}
+		page, err := loadPage(root, strings.TrimPrefix(fp, root+"/"), data, shortcodes)
+		if err != nil {
+			// Shortcode errors already name the file.
+			var scErr *ShortcodeError
+			if errors.As(err, &scErr) {
+				return err
+			}
+			var fmErr *FrontMatterError
+			if errors.As(err, &fmErr) {
+				idx.skipped[fp] = err
+				return nil
+			}
+			return fmt.Errorf("%s: %w", fp, err)
+		}
+		idx.add(page)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to index %s: %w", root, err)
+	}
+
+	for bundle, files := range resources {
+		rel := strings.TrimPrefix(bundle, root+"/")
+		if page := idx.byPath[rel]; page != nil {
+			sort.Strings(files)
+			page.Resources = files
+		}
+	}
+	idx.buildSeries()
+	if err := idx.resolveRefs(root); err != nil {
+		return nil, err
+	}
+
+	return idx, nil
+}
+
+// loadPage parses the content file rel of the root directory into a
+// Page. Shortcodes are expanded and markdown content is rendered to
+// HTML. Shortcode errors name the file by its path with root.
+func loadPage(root, rel string, data []byte, shortcodes Shortcodes) (*Page, error) {
+	fm, meta, content, err := ParseFrontMatter(data)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page{
+		Path:        rel,
+		Content:     content,
+		Meta:        meta,
+		FrontMatter: &fm,
+		Section:     strings.Split(rel, "/")[0],
+		Kind:        KindPage,
+	}
+
+	lineOffset := bytes.Count(data[:len(data)-len(content)], []byte("\n"))
+	switch path.Ext(rel) {
+	case ".md":
+		content, placeholders, err := expandShortcodes(page, path.Join(root, rel), shortcodes, content, lineOffset, true)
+		if err != nil {
+			return nil, err
+		}
+		// Parse once so the rendered heading IDs and the table of
+		// contents come from the same document.
+		doc := Markdown.Parser().Parse(text.NewReader(content))
+		var buf bytes.Buffer
+		if err := Markdown.Renderer().Render(&buf, content, doc); err != nil {
+			return nil, err
+		}
+		page.Type = "markdown"
+		page.Content = restoreShortcodes(buf.Bytes(), placeholders)
+		page.stats = markdownStats(doc, content)
+	case ".html":
+		content, _, err := expandShortcodes(page, path.Join(root, rel), shortcodes, content, lineOffset, false)
+		if err != nil {
+			return nil, err
+		}
+		page.Type = "html"
+		page.Content = content
+		page.stats = htmlStats(content)
+	default:
+		return nil, fmt.Errorf("%s is unsupported", rel)
+	}
+
+	name := strings.TrimSuffix(path.Base(rel), path.Ext(rel))
+	if name == "_index" {
+		page.Kind = KindSection
+		if path.Dir(rel) == "." {
+			page.Kind = KindHome
+			page.Section = ""
+		}
+	} else if path.Dir(rel) == "." {
+		page.Section = ""
+	}
+
+	if len(page.refs) > 0 {
+		page.unresolved = page.Content
+	}
+	return page, nil
+}
+
+func (idx *ContentIndex) add(page *Page) {
+	idx.pages = append(idx.pages, page)
+	idx.byPath[page.Path] = page
+	idx.byPath[pageKey(page.Path)] = page
+
+	if page.Kind != KindPage {
+		idx.lists[page.Section] = page
+		return
+	}
+
+	if _, exists := idx.bySlug[page.Slug()]; !exists {
+		idx.bySlug[page.Slug()] = page
+	}
+	name := path.Base(pageKey(page.Path))
+	idx.byName[name] = append(idx.byName[name], page)
+	idx.sections[page.Section] = append(idx.sections[page.Section], page)
+}
+
+// Skipped returns the content files left out of the index for invalid
+// front matter, with their errors.
+func (idx *ContentIndex) Skipped() map[string]error {
+	return idx.skipped
+}
+
+// Pages returns every indexed page including section list pages.
+func (idx *ContentIndex) Pages() []*Page {
+	return idx.pages
+}
+
+// ByPath returns the page at a content relative path. The extension,
+// a trailing slash and bundle "index" names are optional, so
+// "blog/first-post", "blog/first-post.md" and "projects/b2mfg/" all
+// resolve.
+func (idx *ContentIndex) ByPath(p string) *Page {
+	p = strings.Trim(path.Clean("/"+p), "/")
+	if page, ok := idx.byPath[p]; ok {
+		return page
+	}
+	return idx.byPath[pageKey(p)]
+}
+
+// BySlug returns the regular page with the given slug.
+func (idx *ContentIndex) BySlug(slug string) *Page {
+	return idx.bySlug[slug]
+}
+
+// Lookup resolves a reference the way Hugo's GetPage does: by path,
+// then by slug, then by an unambiguous file or bundle name.
+func (idx *ContentIndex) Lookup(ref string) *Page {
+	if ref == "" {
+		return nil
+	}
+	if page := idx.ByPath(ref); page != nil {
+		return page
+	}
+	if page := idx.BySlug(ref); page != nil {
+		return page
+	}
+	if pages := idx.byName[path.Base(pageKey(ref))]; len(pages) == 1 {
+		return pages[0]
+	}
+	return nil
+}
+
+// Section returns the regular pages of a section in walk order.
+func (idx *ContentIndex) Section(section string) []*Page {
+	return idx.sections[section]
+}
+
+// SectionPage returns the _index page of a section, or nil.
+func (idx *ContentIndex) SectionPage(section string) *Page {
+	return idx.lists[section]
+}
+
+// Sections returns the names of all top level sections.
+func (idx *ContentIndex) Sections() []string {
+	var names []string
+	for name := range idx.sections {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetPermalinks sets the RelPermalink of every page from the site
+// permalink rules and updates the links of ref shortcodes to match.
+func (idx *ContentIndex) SetPermalinks(permalinks Permalinks) {
+	for _, page := range idx.pages {
+		page.RelPermalink = permalinks.URL(page)
+	}
+	idx.renderRefs()
+}
+
+// pageKey normalizes a content path by dropping the extension and a
+// trailing index or _index name.
+func pageKey(p string) string {
+	p = strings.TrimSuffix(p, path.Ext(p))
+	switch path.Base(p) {
+	case "index", "_index":
+		p = path.Dir(p)
+	}
+	if p == "." {
+		return ""
+	}
+	return p
+}
+
+// bundleOf returns the leaf bundle directory containing dir, if any.
+func bundleOf(bundles map[string]bool, dir string) string {
+	for d := dir; d != "." && d != "/" && d != ""; d = path.Dir(d) {
+		if bundles[d] {
+			return d
+		}
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+package portfolio
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testContentFS() fstest.MapFS {
+	return fstest.MapFS{
+		"content/_index.md":                  {Data: []byte("---\ntitle: Home\n---\nHello\n")},
+		"content/about.md":                   {Data: []byte("---\ntitle: About\n---\nAbout me\n")},
+		"content/blog/_index.html":           {Data: []byte("---\ntitle: Blog entries\n---\n<p>Entries</p>\n")},
+		"content/blog/first-post.md":         {Data: []byte("---\ntitle: Welcome\nslug: welcome\n---\n## Hi\n")},
+		"content/blog/second.md":             {Data: []byte("---\ntitle: Second\n---\nBody\n")},
+		"content/projects/_index.html":       {Data: []byte("---\ntitle: Projects\n---\n")},
+		"content/projects/b2mfg/index.md":    {Data: []byte("---\ntitle: B2MFG\n---\nSite\n")},
+		"content/projects/b2mfg/b2mfg.png":   {Data: []byte("png")},
+		"content/projects/b2mfg/extra.md":    {Data: []byte("---\ntitle: Extra\n---\n")},
+		"content/projects/primomed/index.md": {Data: []byte("---\ntitle: Primomed\nslug: primo\n---\n")},
+	}
+}
+
+func TestContentIndexKinds(t *testing.T) {
+	idx, err := NewContentIndex(context.Background(), testContentFS(), "content")
+	require.NoError(t, err)
+
+	home := idx.ByPath("")
+	require.NotNil(t, home)
+	assert.Equal(t, KindHome, home.Kind)
+
+	blog := idx.SectionPage("blog")
+	require.NotNil(t, blog)
+	assert.Equal(t, KindSection, blog.Kind)
+	assert.Equal(t, "html", blog.Type)
+	assert.Equal(t, "Blog entries", blog.Title())
+
+	about := idx.ByPath("about")
+	require.NotNil(t, about)
+	assert.Equal(t, KindPage, about.Kind)
+	assert.Equal(t, "", about.Section)
+
+	assert.Equal(t, []string{"blog", "projects"}, idx.Sections())
+}
+
+func TestContentIndexBundles(t *testing.T) {
+	idx, err := NewContentIndex(context.Background(), testContentFS(), "content")
+	require.NoError(t, err)
+
+	bundle := idx.ByPath("projects/b2mfg/")
+	require.NotNil(t, bundle)
+	assert.Equal(t, "projects/b2mfg/index.md", bundle.Path)
+	assert.Equal(t, "b2mfg", bundle.Slug())
+	assert.Equal(t, []string{"b2mfg.png", "extra.md"}, bundle.Resources)
+
+	// Markdown files inside a leaf bundle are resources, not pages.
+	assert.Nil(t, idx.ByPath("projects/b2mfg/extra"))
+	assert.Len(t, idx.Section("projects"), 2)
+}
+
+func TestContentIndexLookup(t *testing.T) {
+	idx, err := NewContentIndex(context.Background(), testContentFS(), "content")
+	require.NoError(t, err)
+
+	tests := []struct {
+		ref  string
+		path string
+	}{
+		{ref: "blog/first-post.md", path: "blog/first-post.md"},
+		{ref: "blog/first-post", path: "blog/first-post.md"},
+		{ref: "/blog/first-post/", path: "blog/first-post.md"},
+		{ref: "welcome", path: "blog/first-post.md"},
+		{ref: "first-post", path: "blog/first-post.md"},
+		{ref: "primo", path: "projects/primomed/index.md"},
+		{ref: "blog", path: "blog/_index.html"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			page := idx.Lookup(tt.ref)
+			require.NotNil(t, page)
+			assert.Equal(t, tt.path, page.Path)
+		})
+	}
+
+	assert.Nil(t, idx.Lookup(""))
+	assert.Nil(t, idx.Lookup("missing"))
+}
+
+func TestContentIndexSection(t *testing.T) {
+	idx, err := NewContentIndex(context.Background(), testContentFS(), "content")
+	require.NoError(t, err)
+
+	posts := idx.Section("blog")
+	require.Len(t, posts, 2)
+	for _, post := range posts {
+		assert.Equal(t, "blog", post.Section)
+		assert.Equal(t, KindPage, post.Kind)
+		assert.Equal(t, "markdown", post.Type)
+	}
+	assert.Contains(t, string(idx.BySlug("welcome").Content), "<h2")
+	assert.Empty(t, idx.Section("missing"))
+}
+
+func TestContentIndexSkipsInvalidFrontMatter(t *testing.T) {
+	fsys := testContentFS()
+	fsys["content/blog/broken.md"] = &fstest.MapFile{Data: []byte("---\ntitle: [Broken\n---\nBody\n")}
+	fsys["content/blog/bad-date.md"] = &fstest.MapFile{Data: []byte("---\ntitle: Bad date\ndate: someday\n---\n")}
+
+	idx, err := NewContentIndex(context.Background(), fsys, "content")
+	require.NoError(t, err)
+	assert.Len(t, idx.Section("blog"), 2)
+	assert.Nil(t, idx.ByPath("blog/broken.md"))
+
+	skipped := idx.Skipped()
+	require.Len(t, skipped, 2)
+	assert.ErrorContains(t, skipped["content/blog/broken.md"], "front matter line")
+	assert.ErrorContains(t, skipped["content/blog/bad-date.md"], `front matter line 3 "date"`)
+}
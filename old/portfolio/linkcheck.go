@@ -0,0 +1,323 @@
+package portfolio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LinkFetcher checks an external URL and returns its HTTP status.
+type LinkFetcher interface {
+	Fetch(ctx context.Context, rawURL string) (int, error)
+}
+
+// LinkFetcherFunc adapts a function to a LinkFetcher.
+type LinkFetcherFunc func(ctx context.Context, rawURL string) (int, error)
+
+func (f LinkFetcherFunc) Fetch(ctx context.Context, rawURL string) (int, error) {
+	return f(ctx, rawURL)
+}
+
+// HTTPFetcher fetches external links over HTTP. It tries HEAD first and
+// falls back to GET for servers that do not support it.
+type HTTPFetcher struct {
+	Client *http.Client
+}
+
+func (f HTTPFetcher) Fetch(ctx context.Context, rawURL string) (int, error) {
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	status := 0
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+		if err != nil {
+			return 0, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		status = resp.StatusCode
+		if status != http.StatusMethodNotAllowed && status != http.StatusNotImplemented {
+			break
+		}
+	}
+	return status, nil
+}
+
+// BrokenLink is a link that does not resolve.
+type BrokenLink struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Href   string `json:"href"`
+	Reason string `json:"reason"`
+}
+
+func (l BrokenLink) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", l.File, l.Line, l.Href, l.Reason)
+}
+
+// LinkReport is the result of checking every link in the content tree.
+type LinkReport struct {
+	Broken   []BrokenLink `json:"broken"`
+	Checked  int          `json:"checked"`
+	External int          `json:"external"`
+}
+
+// HasBroken reports whether any link is broken.
+func (r LinkReport) HasBroken() bool {
+	return len(r.Broken) > 0
+}
+
+// WriteText writes one broken link per line followed by a summary.
+func (r LinkReport) WriteText(w io.Writer) error {
+	for _, link := range r.Broken {
+		if _, err := fmt.Fprintln(w, link); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d link(s) checked, %d external, %d broken\n",
+		r.Checked, r.External, len(r.Broken))
+	return err
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r LinkReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// LinkChecker verifies internal links and #fragment anchors across the
+// content tree, and optionally external links.
+type LinkChecker struct {
+	// FS holds the site. Root is the content directory within it and
+	// static files are looked up in a static directory next to Root.
+	FS   fs.FS
+	Root string
+
+	Permalinks Permalinks
+	// BaseURL makes absolute links to the site itself count as internal.
+	BaseURL string
+	// Fetcher checks external links. External links are skipped when
+	// nil.
+	Fetcher LinkFetcher
+}
+
+// linkTarget is a page that links can point at.
+type linkTarget struct {
+	url     string
+	anchors map[string]bool
+}
+
+// pageLink is a link found in a content file.
+type pageLink struct {
+	href   string
+	offset int
+}
+
+// Check renders every page and resolves each link it contains.
+func (c *LinkChecker) Check(ctx context.Context) (LinkReport, error) {
+	var report LinkReport
+
+	idx, err := NewContentIndex(ctx, c.FS, c.Root)
+	if err != nil {
+		return report, err
+	}
+
+	var site *url.URL
+	if c.BaseURL != "" {
+		if site, err = url.Parse(c.BaseURL); err != nil {
+			return report, fmt.Errorf("invalid base URL: %w", err)
+		}
+	}
+
+	targets := make(map[string]*linkTarget)
+	files := make(map[string]bool)
+	for _, page := range idx.Pages() {
+		target := &linkTarget{
+			url:     c.Permalinks.URL(page),
+			anchors: make(map[string]bool),
+		}
+		for _, m := range htmlID.FindAllSubmatch(page.Content, -1) {
+			target.anchors[string(m[1])] = true
+		}
+		targets[linkKey(target.url)] = target
+
+		aliases, _ := asStrings(page.Meta["aliases"])
+		for _, alias := range aliases {
+			targets[linkKey(alias)] = target
+		}
+		for _, res := range page.Resources {
+			files[target.url+res] = true
+		}
+	}
+
+	external := make(map[string]string)
+	for _, page := range idx.Pages() {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		file := path.Join(c.Root, page.Path)
+		data, err := fs.ReadFile(c.FS, file)
+		if err != nil {
+			return report, err
+		}
+		_, _, body, err := ParseFrontMatter(data)
+		if err != nil {
+			return report, fmt.Errorf("%s: %w", file, err)
+		}
+		head := len(data) - len(body)
+
+		// Links come from the rendered page so that shortcodes such as
+		// ref and figure are checked too. Each is traced back to the
+		// source for its line number.
+		var links []pageLink
+		pos := 0
+		for _, href := range htmlLinks(page.Content) {
+			pos = sourceOffset(body, href, pos)
+			links = append(links, pageLink{href, pos})
+		}
+
+		from := targets[linkKey(c.Permalinks.URL(page))]
+		for _, link := range links {
+			report.Checked++
+			broken := func(reason string) {
+				report.Broken = append(report.Broken, BrokenLink{
+					File:   file,
+					Line:   bytes.Count(data[:head+link.offset], []byte("\n")) + 1,
+					Href:   link.href,
+					Reason: reason,
+				})
+			}
+
+			u, err := url.Parse(link.href)
+			if err != nil {
+				broken("malformed URL")
+				continue
+			}
+
+			switch {
+			case u.Scheme == "" && u.Host == "":
+			case site != nil && u.Host == site.Host:
+			case u.Scheme == "http" || u.Scheme == "https":
+				report.External++
+				if c.Fetcher == nil {
+					continue
+				}
+				reason, seen := external[link.href]
+				if !seen {
+					reason = c.fetch(ctx, link.href)
+					external[link.href] = reason
+				}
+				if reason != "" {
+					broken(reason)
+				}
+				continue
+			default:
+				// mailto:, tel: and friends cannot be checked.
+				continue
+			}
+
+			target := from
+			if u.Path != "" {
+				p := u.Path
+				if !strings.HasPrefix(p, "/") {
+					p = path.Join(from.url, p)
+				}
+				if files[p] || c.staticExists(p) {
+					continue
+				}
+				target = targets[linkKey(p)]
+				if target == nil {
+					broken(fmt.Sprintf("no page or file at %s", p))
+					continue
+				}
+			}
+			if u.Fragment != "" && !target.anchors[u.Fragment] {
+				broken(fmt.Sprintf("no anchor #%s on %s", u.Fragment, target.url))
+			}
+		}
+	}
+
+	sort.SliceStable(report.Broken, func(i, j int) bool {
+		a, b := report.Broken[i], report.Broken[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return report, nil
+}
+
+// fetch returns why an external link is broken, or "" when it is fine.
+func (c *LinkChecker) fetch(ctx context.Context, rawURL string) string {
+	status, err := c.Fetcher.Fetch(ctx, rawURL)
+	if err != nil {
+		return err.Error()
+	}
+	if status >= 400 {
+		return fmt.Sprintf("%d %s", status, http.StatusText(status))
+	}
+	return ""
+}
+
+func (c *LinkChecker) staticExists(p string) bool {
+	if strings.HasSuffix(p, "/") {
+		return false
+	}
+	info, err := fs.Stat(c.FS, path.Join(path.Dir(c.Root), "static", p))
+	return err == nil && !info.IsDir()
+}
+
+// linkKey normalizes a site relative URL path for lookups.
+func linkKey(p string) string {
+	p = strings.TrimSuffix(p, "index.html")
+	return "/" + strings.Trim(path.Clean("/"+p), "/")
+}
+
+var (
+	htmlID   = regexp.MustCompile(`(?:^|[\s<])id\s*=\s*["']([^"']+)["']`)
+	htmlHref = regexp.MustCompile(`(?i)(?:^|[\s<])(?:href|src)\s*=\s*["']([^"']*)["']`)
+)
+
+// htmlLinks returns the unescaped href and src attributes in HTML.
+func htmlLinks(src []byte) []string {
+	var hrefs []string
+	for _, m := range htmlHref.FindAllSubmatch(src, -1) {
+		hrefs = append(hrefs, html.UnescapeString(string(m[1])))
+	}
+	return hrefs
+}
+
+// sourceOffset returns where href appears in the source, preferring
+// the first match at or after the previous link at from. A link the
+// source does not spell out, such as a ref, is placed at the next
+// shortcode.
+func sourceOffset(src []byte, href string, from int) int {
+	if i := bytes.Index(src[from:], []byte(href)); i >= 0 {
+		return from + i
+	}
+	if i := bytes.Index(src, []byte(href)); i >= 0 {
+		return i
+	}
+	if i := bytes.Index(src[from:], []byte("{{")); i >= 0 {
+		return from + i
+	}
+	return from
+}
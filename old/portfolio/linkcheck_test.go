@@ -0,0 +1,227 @@
+package portfolio
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermalinksURL(t *testing.T) {
+	permalinks := ParsePermalinks(map[string]any{
+		"blog":    "/blog/:slug",
+		"notes":   "/:year/:month/:filename",
+		"page":    map[string]any{"blog": "/posts/:slug/"},
+		"section": map[string]any{"blog": "/writing/"},
+	})
+
+	tests := []struct {
+		name string
+		page *Page
+		want string
+	}{
+		{
+			name: "page rule wins over flat rule",
+			page: testPage("blog/first.md", "---\ntitle: First Post\nslug: hello\n---\n"),
+			want: "/posts/hello/",
+		},
+		{
+			name: "slug falls back to title",
+			page: testPage("blog/first.md", "---\ntitle: First Post!\n---\n"),
+			want: "/posts/first-post/",
+		},
+		{
+			name: "date and filename tokens",
+			page: testPage("notes/today.md", "---\ntitle: Today\ndate: 2025-03-09\n---\n"),
+			want: "/2025/03/today/",
+		},
+		{
+			name: "default uses slug or filename",
+			page: testPage("projects/site/index.md", "---\ntitle: Site\n---\n"),
+			want: "/projects/site/",
+		},
+		{
+			name: "root page",
+			page: testPage("about.md", "---\ntitle: About\nslug: me\n---\n"),
+			want: "/me/",
+		},
+		{
+			name: "url override",
+			page: testPage("about.md", "---\ntitle: About\nurl: about-me/\n---\n"),
+			want: "/about-me/",
+		},
+		{
+			name: "section rule",
+			page: testPage("blog/_index.md", "---\ntitle: Blog\n---\n"),
+			want: "/writing/",
+		},
+		{
+			name: "default section",
+			page: testPage("projects/_index.md", "---\ntitle: Projects\n---\n"),
+			want: "/projects/",
+		},
+		{
+			name: "home",
+			page: testPage("_index.md", "---\ntitle: Home\n---\n"),
+			want: "/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, permalinks.URL(tt.page))
+		})
+	}
+}
+
+func testPage(rel, src string) *Page {
+	page, err := loadPage("content", rel, []byte(src), BuiltinShortcodes())
+	if err != nil {
+		panic(err)
+	}
+	return page
+}
+
+func linkFixture() fstest.MapFS {
+	return fstest.MapFS{
+		"content/_index.md": {Data: []byte(
+			"---\ntitle: Home\n---\n[Blog](/blog/) and [posts](/blog/first/#setup)\n",
+		)},
+		"content/blog/_index.md": {Data: []byte("---\ntitle: Blog\n---\n")},
+		"content/blog/first.md": {Data: []byte(joinLines(
+			"---",
+			"title: First",
+			"slug: first",
+			"---",
+			"## Setup",
+			"",
+			"See [the second post](/blog/second/#usage) and [below](#setup).",
+			"",
+			"Broken [anchor](#nowhere) and [page](/blog/missing/).",
+			"",
+			"![logo](/img/logo.png) ![gone](/img/gone.png)",
+			"",
+			"[Home](https://example.com/) [Out](https://ok.example/) [Down](https://down.example/)",
+			"",
+			`<a href="/projects/site/cover.png">cover</a> <a href="mailto:me@example.com">mail</a>`,
+		))},
+		"content/blog/second.md": {Data: []byte(joinLines(
+			"---",
+			"title: Second",
+			"slug: second",
+			"aliases: [/old/second/]",
+			"---",
+			"## Usage {#usage}",
+			"",
+			"[Old](/old/second/) [Down again](https://down.example/)",
+			"",
+			`{{< figure src="/img/nope.png" alt="Nope" >}}`,
+			"",
+			`[Gone]({{< ref "blog/first#gone" >}})`,
+		))},
+		"content/about.html": {Data: []byte(
+			"---\ntitle: About\n---\n<p data-id=\"me\">Me</p>\n<a href=\"#me\">me</a>\n",
+		)},
+		"content/projects/site/index.md": {Data: []byte(
+			"---\ntitle: Site\n---\n![cover](cover.png) ![other](other.png)\n",
+		)},
+		"content/projects/site/cover.png": {Data: []byte("png")},
+		"static/img/logo.png":             {Data: []byte("png")},
+	}
+}
+
+func TestLinkChecker(t *testing.T) {
+	var fetched []string
+	checker := &LinkChecker{
+		FS:         linkFixture(),
+		Root:       "content",
+		Permalinks: ParsePermalinks(map[string]any{"blog": "/blog/:slug"}),
+		BaseURL:    "https://example.com",
+		Fetcher: LinkFetcherFunc(func(ctx context.Context, rawURL string) (int, error) {
+			fetched = append(fetched, rawURL)
+			if rawURL == "https://down.example/" {
+				return http.StatusNotFound, nil
+			}
+			return http.StatusOK, nil
+		}),
+	}
+
+	report, err := checker.Check(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []BrokenLink{
+		{File: "content/about.html", Line: 5, Href: "#me", Reason: "no anchor #me on /about/"},
+		{File: "content/blog/first.md", Line: 9, Href: "#nowhere", Reason: "no anchor #nowhere on /blog/first/"},
+		{File: "content/blog/first.md", Line: 9, Href: "/blog/missing/", Reason: "no page or file at /blog/missing/"},
+		{File: "content/blog/first.md", Line: 11, Href: "/img/gone.png", Reason: "no page or file at /img/gone.png"},
+		{File: "content/blog/first.md", Line: 13, Href: "https://down.example/", Reason: "404 Not Found"},
+		{File: "content/blog/second.md", Line: 8, Href: "https://down.example/", Reason: "404 Not Found"},
+		{File: "content/blog/second.md", Line: 10, Href: "/img/nope.png", Reason: "no page or file at /img/nope.png"},
+		{File: "content/blog/second.md", Line: 12, Href: "/blog/first/#gone", Reason: "no anchor #gone on /blog/first/"},
+		{File: "content/projects/site/index.md", Line: 4, Href: "other.png", Reason: "no page or file at /projects/site/other.png"},
+	}, report.Broken)
+	assert.Equal(t, 18, report.Checked)
+	assert.Equal(t, 3, report.External)
+	assert.ElementsMatch(t, []string{"https://ok.example/", "https://down.example/"}, fetched)
+}
+
+func TestLinkCheckerSkipsExternalWithoutFetcher(t *testing.T) {
+	checker := &LinkChecker{
+		FS:         linkFixture(),
+		Root:       "content",
+		Permalinks: ParsePermalinks(map[string]any{"blog": "/blog/:slug"}),
+	}
+
+	report, err := checker.Check(context.Background())
+	require.NoError(t, err)
+	for _, link := range report.Broken {
+		assert.NotContains(t, link.Href, "down.example")
+	}
+}
+
+func TestHTTPFetcher(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/no-head" && r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case r.URL.Path == "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	fetcher := HTTPFetcher{Client: srv.Client()}
+	ctx := context.Background()
+
+	status, err := fetcher.Fetch(ctx, srv.URL+"/")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, err = fetcher.Fetch(ctx, srv.URL+"/no-head")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, err = fetcher.Fetch(ctx, srv.URL+"/missing")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestLinkReportText(t *testing.T) {
+	report := LinkReport{
+		Broken:  []BrokenLink{{File: "content/a.md", Line: 3, Href: "/b/", Reason: "no page or file at /b/"}},
+		Checked: 4,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteText(&buf))
+	assert.Equal(t,
+		"content/a.md:3: /b/: no page or file at /b/\n4 link(s) checked, 0 external, 1 broken\n",
+		buf.String(),
+	)
+	assert.True(t, report.HasBroken())
+}
@@ -0,0 +1,392 @@
+package portfolio
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// LintSeverity is the severity of a lint issue.
+type LintSeverity string
+
+const (
+	SeverityError   LintSeverity = "error"
+	SeverityWarning LintSeverity = "warning"
+)
+
+// MaxDescriptionLength is the longest description that search engines
+// reliably show in full.
+const MaxDescriptionLength = 160
+
+// LintIssue is a single problem found in a content file.
+type LintIssue struct {
+	File     string       `json:"file"`
+	Line     int          `json:"line,omitempty"`
+	Severity LintSeverity `json:"severity"`
+	Rule     string       `json:"rule"`
+	Message  string       `json:"message"`
+}
+
+func (i LintIssue) String() string {
+	loc := i.File
+	if i.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", i.File, i.Line)
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", loc, i.Severity, i.Message, i.Rule)
+}
+
+// LintReport is the result of linting a content tree.
+type LintReport struct {
+	Issues   []LintIssue `json:"issues"`
+	Errors   int         `json:"errors"`
+	Warnings int         `json:"warnings"`
+}
+
+// HasErrors reports whether any issue is an error.
+func (r LintReport) HasErrors() bool {
+	return r.Errors > 0
+}
+
+// WriteText writes one issue per line followed by a summary.
+func (r LintReport) WriteText(w io.Writer) error {
+	for _, issue := range r.Issues {
+		if _, err := fmt.Fprintln(w, issue); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d error(s), %d warning(s)\n", r.Errors, r.Warnings)
+	return err
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r LintReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// lintFile is a parsed content file awaiting cross-file checks.
+type lintFile struct {
+	path  string
+	head  []byte
+	body  []byte
+	front FrontMatter
+	raw   map[string]any
+	bad   map[string]bool
+	list  bool
+}
+
+// Lint checks every content file under root against the front matter
+// schema used by the site.
+func Lint(fsys fs.FS, root string) (LintReport, error) {
+	var report LintReport
+	add := func(issue LintIssue) {
+		report.Issues = append(report.Issues, issue)
+		if issue.Severity == SeverityError {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+
+	bundles := make(map[string]bool)
+	var files []*lintFile
+	err := fs.WalkDir(fsys, root, func(fp string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && d.Name() == "index.md" {
+			bundles[path.Dir(fp)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	err = fs.WalkDir(fsys, root, func(fp string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := path.Ext(fp)
+		if d.IsDir() || (ext != ".md" && ext != ".html") {
+			return nil
+		}
+		if bundle := bundleOf(bundles, path.Dir(fp)); bundle != "" && d.Name() != "index.md" {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, fp)
+		if err != nil {
+			return err
+		}
+		file := &lintFile{
+			path: fp,
+			list: strings.HasPrefix(d.Name(), "_index."),
+			bad:  make(map[string]bool),
+		}
+
+		fm, raw, body, err := ParseFrontMatter(data)
+		file.front, file.raw = fm, raw
+		file.head, file.body = data[:len(data)-len(body)], body
+		for _, e := range frontMatterErrors(err) {
+			file.bad[e.Key] = true
+			add(LintIssue{
+				File:     fp,
+				Line:     e.Line,
+				Severity: SeverityError,
+				Rule:     "front-matter",
+				Message:  e.Error(),
+			})
+		}
+		if err == nil || len(raw) > 0 {
+			files = append(files, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	for _, file := range files {
+		for _, issue := range lintPage(fsys, root, file) {
+			add(issue)
+		}
+	}
+	for _, issue := range lintDuplicates(root, files) {
+		add(issue)
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		a, b := report.Issues[i], report.Issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return report, nil
+}
+
+var canonicalDate = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2}))?$`)
+
+// lintPage runs the single file checks.
+func lintPage(fsys fs.FS, root string, file *lintFile) []LintIssue {
+	var issues []LintIssue
+	issue := func(key string, severity LintSeverity, rule, format string, args ...any) {
+		issues = append(issues, LintIssue{
+			File:     file.path,
+			Line:     file.line(key),
+			Severity: severity,
+			Rule:     rule,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	fm := file.front
+	if file.front.Format == FormatNone {
+		issue("", SeverityError, "front-matter", "missing front matter")
+		return issues
+	}
+	if fm.Title == "" {
+		issue("", SeverityError, "title", "missing title")
+	}
+
+	// Section list pages and standalone pages with a url are not part of
+	// a dated, slugged listing.
+	_, hasURL := file.raw["url"]
+	regular := !file.list && !hasURL
+	if regular && fm.Slug == "" {
+		issue("", SeverityWarning, "slug", "missing slug")
+	}
+	if _, ok := file.raw["date"]; regular && !ok {
+		issue("", SeverityWarning, "date", "missing date")
+	}
+
+	for _, key := range []string{"date", "lastmod", "publishDate", "expiryDate"} {
+		value, ok := file.raw[key].(string)
+		if ok && value != "" && !file.bad[key] && !canonicalDate.MatchString(value) {
+			issue(key, SeverityWarning, "date-format",
+				"%s %q should be YYYY-MM-DD or RFC 3339", key, value)
+		}
+	}
+
+	if n := utf8.RuneCountInString(fm.Description); n > MaxDescriptionLength {
+		issue("description", SeverityWarning, "description-length",
+			"description is %d characters, over the %d character limit",
+			n, MaxDescriptionLength)
+	}
+
+	if _, ok := file.raw["tags"]; ok {
+		if len(fm.Tags) == 0 {
+			issue("tags", SeverityWarning, "tags", "tags is empty")
+		}
+		for i, tag := range fm.Tags {
+			if strings.TrimSpace(tag) == "" {
+				issue("tags", SeverityError, "tags", "tag %d is empty", i+1)
+			}
+		}
+	}
+
+	dir := path.Dir(file.path)
+	exists := func(name string) bool {
+		if strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://") {
+			return true
+		}
+		if strings.HasPrefix(name, "/") {
+			// Site absolute paths are served from static/ next to the
+			// content directory.
+			_, err := fs.Stat(fsys, path.Join(path.Dir(root), "static", name))
+			return err == nil
+		}
+		// Relative paths resolve against the page's directory, which is
+		// the bundle itself for index.md pages.
+		_, err := fs.Stat(fsys, path.Join(dir, name))
+		return err == nil
+	}
+
+	if fm.Image != "" {
+		if !exists(fm.Image) {
+			issue("image", SeverityError, "image", "image %q does not exist", fm.Image)
+		}
+		if alt, _ := file.raw["imgAlt"].(string); strings.TrimSpace(alt) == "" {
+			issue("image", SeverityWarning, "alt", "image %q is missing imgAlt", fm.Image)
+		}
+	}
+	for i, img := range fm.Gallery {
+		if img.File == "" {
+			issue("gallery", SeverityError, "gallery", "gallery item %d is missing file", i+1)
+			continue
+		}
+		if !exists(img.File) {
+			issue("gallery", SeverityError, "gallery", "gallery image %q does not exist", img.File)
+		}
+		if strings.TrimSpace(img.Alt) == "" {
+			issue("gallery", SeverityWarning, "alt", "gallery image %q is missing alt", img.File)
+		}
+	}
+
+	if path.Ext(file.path) == ".md" {
+		offset := bytes.Count(file.head, []byte("\n"))
+		for _, problem := range mathErrors(file.body) {
+			issues = append(issues, LintIssue{
+				File:     file.path,
+				Line:     offset + problem.line,
+				Severity: SeverityWarning,
+				Rule:     "math",
+				Message:  problem.err.Msg,
+			})
+		}
+	}
+
+	return issues
+}
+
+// lintDuplicates reports slugs repeated within a section and
+// descriptions repeated anywhere.
+func lintDuplicates(root string, files []*lintFile) []LintIssue {
+	var issues []LintIssue
+
+	slugs := make(map[string][]*lintFile)
+	descriptions := make(map[string][]*lintFile)
+	for _, file := range files {
+		if file.list {
+			continue
+		}
+		rel := strings.TrimPrefix(file.path, root+"/")
+		section := strings.Split(rel, "/")[0]
+		if !strings.Contains(rel, "/") {
+			section = ""
+		}
+		page := &Page{Path: rel, FrontMatter: &file.front}
+		key := section + "/" + page.Slug()
+		slugs[key] = append(slugs[key], file)
+
+		if desc := strings.TrimSpace(file.front.Description); desc != "" {
+			descriptions[desc] = append(descriptions[desc], file)
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(slugs)) {
+		dupes := slugs[key]
+		if len(dupes) < 2 {
+			continue
+		}
+		_, slug, _ := strings.Cut(key, "/")
+		for _, file := range dupes {
+			issues = append(issues, LintIssue{
+				File:     file.path,
+				Line:     file.line("slug"),
+				Severity: SeverityError,
+				Rule:     "duplicate-slug",
+				Message:  fmt.Sprintf("slug %q is also used by %s", slug, others(dupes, file)),
+			})
+		}
+	}
+
+	for _, desc := range slices.Sorted(maps.Keys(descriptions)) {
+		dupes := descriptions[desc]
+		if len(dupes) < 2 {
+			continue
+		}
+		for _, file := range dupes {
+			issues = append(issues, LintIssue{
+				File:     file.path,
+				Line:     file.line("description"),
+				Severity: SeverityWarning,
+				Rule:     "duplicate-description",
+				Message:  fmt.Sprintf("description is also used by %s", others(dupes, file)),
+			})
+		}
+	}
+
+	return issues
+}
+
+// line returns the line of a front matter key, or 1 for the file.
+func (f *lintFile) line(key string) int {
+	if key != "" {
+		if line := keyLine(f.head, key); line > 0 {
+			return line
+		}
+	}
+	return 1
+}
+
+func others(files []*lintFile, self *lintFile) string {
+	var paths []string
+	for _, file := range files {
+		if file != self {
+			paths = append(paths, file.path)
+		}
+	}
+	return strings.Join(paths, ", ")
+}
+
+// frontMatterErrors flattens joined front matter errors.
+func frontMatterErrors(err error) []*FrontMatterError {
+	if err == nil {
+		return nil
+	}
+	var out []*FrontMatterError
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			out = append(out, frontMatterErrors(e)...)
+		}
+		return out
+	}
+	var fmErr *FrontMatterError
+	if errors.As(err, &fmErr) {
+		return []*FrontMatterError{fmErr}
+	}
+	return []*FrontMatterError{{Err: err}}
+}
@@ -0,0 +1,185 @@
+package portfolio
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lintFixture() fstest.MapFS {
+	return fstest.MapFS{
+		"content/_index.md": {Data: []byte("---\ntitle: Home\n---\n")},
+		"content/about.md": {Data: []byte(
+			"---\ntitle: About\nurl: /about/\n---\nAbout me\n",
+		)},
+		"content/blog/first.md": {Data: []byte(
+			"---\ntitle: First\nslug: hello\ndate: 2025-01-06\ndescription: Same words\ntags: [go]\n---\n",
+		)},
+		"content/blog/second.md": {Data: []byte(
+			"---\ntitle: Second\nslug: hello\ndate: \"2025-01-06 10:00:00\"\ndescription: Same words\ntags: []\n---\n",
+		)},
+		"content/blog/broken.md": {Data: []byte(
+			"---\ntitle: Broken\nslug: broken\ndate: someday\n---\n",
+		)},
+		"content/projects/site/index.md": {Data: []byte(joinLines(
+			"---",
+			"title: Site",
+			"slug: site",
+			"date: 2025-12-04",
+			"image: cover.png",
+			"imgAlt: Cover",
+			"gallery:",
+			"  - file: missing.png",
+			"    alt: Missing",
+			"  - file: shot.png",
+			"---",
+		))},
+		"content/projects/site/cover.png": {Data: []byte("png")},
+		"content/projects/site/shot.png":  {Data: []byte("png")},
+		"content/projects/other/index.md": {Data: []byte(
+			"---\ntitle: Other\nslug: site\ndate: 2025-12-04\nimage: /img/logo.png\n---\n",
+		)},
+		"content/notes.md":    {Data: []byte("# No front matter\n")},
+		"static/img/logo.png": {Data: []byte("png")},
+	}
+}
+
+func joinLines(lines ...string) string {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+func TestLint(t *testing.T) {
+	report, err := Lint(lintFixture(), "content")
+	require.NoError(t, err)
+
+	type found struct {
+		file string
+		line int
+		rule string
+	}
+	var got []found
+	for _, issue := range report.Issues {
+		got = append(got, found{issue.File, issue.Line, issue.Rule})
+	}
+
+	expected := []found{
+		{"content/blog/broken.md", 4, "front-matter"},
+		{"content/blog/first.md", 3, "duplicate-slug"},
+		{"content/blog/first.md", 5, "duplicate-description"},
+		{"content/blog/second.md", 3, "duplicate-slug"},
+		{"content/blog/second.md", 4, "date-format"},
+		{"content/blog/second.md", 5, "duplicate-description"},
+		{"content/blog/second.md", 6, "tags"},
+		{"content/notes.md", 1, "front-matter"},
+		{"content/projects/other/index.md", 3, "duplicate-slug"},
+		{"content/projects/other/index.md", 5, "alt"},
+		{"content/projects/site/index.md", 3, "duplicate-slug"},
+		{"content/projects/site/index.md", 7, "gallery"},
+		{"content/projects/site/index.md", 7, "alt"},
+	}
+	for _, want := range expected {
+		assert.Contains(t, got, want)
+	}
+	assert.Len(t, got, len(expected), report.Issues)
+	assert.True(t, report.HasErrors())
+}
+
+func TestLintClean(t *testing.T) {
+	fsys := fstest.MapFS{
+		"content/blog/post.md": {Data: []byte(
+			"---\ntitle: Post\nslug: post\ndate: 2025-01-06\ntags: [go]\n---\nBody\n",
+		)},
+	}
+
+	report, err := Lint(fsys, "content")
+	require.NoError(t, err)
+	assert.Empty(t, report.Issues)
+	assert.False(t, report.HasErrors())
+}
+
+func TestLintPageFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"content/blog/found.md": {Data: []byte(
+			"---\ntitle: Found\nslug: found\ndate: 2025-01-06\nimage: cover.png\nimgAlt: Cover\n---\n",
+		)},
+		"content/blog/lost.md": {Data: []byte(
+			"---\ntitle: Lost\nslug: lost\ndate: 2025-01-06\nimage: gone.png\nimgAlt: Gone\n---\n",
+		)},
+		"content/blog/cover.png": {Data: []byte("png")},
+		"content/blog/accents.md": {Data: []byte(
+			"---\ntitle: Accents\nslug: accents\ndate: 2025-01-06\ndescription: " +
+				strings.Repeat("é", MaxDescriptionLength) + "\n---\n",
+		)},
+	}
+
+	report, err := Lint(fsys, "content")
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1, report.Issues)
+	issue := report.Issues[0]
+	assert.Equal(t, "content/blog/lost.md", issue.File)
+	assert.Equal(t, "image", issue.Rule)
+	assert.Equal(t, `image "gone.png" does not exist`, issue.Message)
+}
+
+func TestLintMath(t *testing.T) {
+	fsys := fstest.MapFS{
+		"content/blog/post.md": {Data: []byte(joinLines(
+			"---",
+			"title: Post",
+			"slug: post",
+			"date: 2025-01-06",
+			"---",
+			"Inline $\\frac{a}{b}$ is fine.",
+			"",
+			"$$",
+			"\\unknown{x}",
+			"$$",
+		))},
+	}
+
+	report, err := Lint(fsys, "content")
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	issue := report.Issues[0]
+	assert.Equal(t, 9, issue.Line)
+	assert.Equal(t, "math", issue.Rule)
+	assert.Equal(t, SeverityWarning, issue.Severity)
+	assert.Equal(t, "unsupported command \\unknown", issue.Message)
+}
+
+func TestLintReportOutput(t *testing.T) {
+	report := LintReport{
+		Issues: []LintIssue{{
+			File:     "content/blog/post.md",
+			Line:     3,
+			Severity: SeverityError,
+			Rule:     "duplicate-slug",
+			Message:  `slug "post" is also used by content/blog/other.md`,
+		}},
+		Errors: 1,
+	}
+
+	var text bytes.Buffer
+	require.NoError(t, report.WriteText(&text))
+	assert.Equal(t,
+		"content/blog/post.md:3: error: slug \"post\" is also used by content/blog/other.md [duplicate-slug]\n"+
+			"1 error(s), 0 warning(s)\n",
+		text.String(),
+	)
+
+	var out bytes.Buffer
+	require.NoError(t, report.WriteJSON(&out))
+	var decoded LintReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, report, decoded)
+}
@@ -0,0 +1,334 @@
+package portfolio
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPerPage = 10
+	MaxPerPage     = 50
+)
+
+// Post sort keys accepted by PostQuery.
+const (
+	SortDate  = "date"
+	SortTitle = "title"
+)
+
+// Draft filters accepted by PostQuery.
+const (
+	DraftExclude = "false"
+	DraftOnly    = "true"
+	DraftAny     = "any"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// PostQuery describes a page of a post listing.
+//
+// Page is 1 based. When Cursor is set it takes precedence over Page and
+// the listing continues after the post the cursor points at.
+type PostQuery struct {
+	Tag     string
+	Year    int
+	Draft   string
+	Sort    string
+	Desc    bool
+	Page    int
+	PerPage int
+	Cursor  string
+}
+
+// PostSummary is the listing representation of a post.
+type PostSummary struct {
+	Title       string    `json:"title"`
+	Slug        string    `json:"slug"`
+	URL         string    `json:"url"`
+	Date        time.Time `json:"date,omitzero"`
+	Description string    `json:"description"`
+	Tags        []string  `json:"tags"`
+	ReadingTime int       `json:"readingTime"`
+	Draft       bool      `json:"draft,omitempty"`
+}
+
+// PostList is a single page of posts.
+type PostList struct {
+	Posts      []PostSummary `json:"posts"`
+	Total      int           `json:"total"`
+	Page       int           `json:"page"`
+	PerPage    int           `json:"perPage"`
+	TotalPages int           `json:"totalPages"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+	PrevCursor string        `json:"prevCursor,omitempty"`
+
+	// hasPrev is set when posts come before the list, even when the
+	// previous page is the first one and so has no cursor.
+	hasPrev bool
+}
+
+// ParsePostQuery reads a PostQuery from URL query parameters:
+// tag, year, draft (true, false or any), sort (date or title), order
+// (asc or desc), page, per_page and cursor.
+func ParsePostQuery(values url.Values) (PostQuery, error) {
+	q := PostQuery{
+		Tag:     strings.TrimSpace(values.Get("tag")),
+		Draft:   DraftExclude,
+		Sort:    SortDate,
+		Page:    1,
+		PerPage: DefaultPerPage,
+		Cursor:  values.Get("cursor"),
+	}
+
+	if v := values.Get("year"); v != "" {
+		year, err := strconv.Atoi(v)
+		if err != nil {
+			return q, fmt.Errorf("invalid year %q", v)
+		}
+		q.Year = year
+	}
+
+	switch v := values.Get("draft"); v {
+	case "":
+	case DraftExclude, DraftOnly, DraftAny:
+		q.Draft = v
+	default:
+		return q, fmt.Errorf("invalid draft filter %q", v)
+	}
+
+	switch v := values.Get("sort"); v {
+	case "":
+	case SortDate, SortTitle:
+		q.Sort = v
+	default:
+		return q, fmt.Errorf("invalid sort %q", v)
+	}
+
+	// Dates default to newest first, titles to alphabetical.
+	q.Desc = q.Sort == SortDate
+	switch v := values.Get("order"); v {
+	case "":
+	case "asc":
+		q.Desc = false
+	case "desc":
+		q.Desc = true
+	default:
+		return q, fmt.Errorf("invalid order %q", v)
+	}
+
+	if v := values.Get("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return q, fmt.Errorf("invalid page %q", v)
+		}
+		q.Page = page
+	}
+
+	if v := values.Get("per_page"); v != "" {
+		perPage, err := strconv.Atoi(v)
+		if err != nil || perPage < 1 {
+			return q, fmt.Errorf("invalid per_page %q", v)
+		}
+		q.PerPage = min(perPage, MaxPerPage)
+	}
+
+	return q, nil
+}
+
+// ListPosts filters, sorts and paginates pages according to q.
+func ListPosts(pages []*Page, q PostQuery) (PostList, error) {
+	if q.PerPage < 1 {
+		q.PerPage = DefaultPerPage
+	}
+	if q.Page < 1 {
+		q.Page = 1
+	}
+
+	var matched []*Page
+	for _, page := range pages {
+		if q.matches(page) {
+			matched = append(matched, page)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return q.less(matched[i], matched[j])
+	})
+
+	start := (q.Page - 1) * q.PerPage
+	if q.Cursor != "" {
+		key, slug, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return PostList{}, err
+		}
+		start = sort.Search(len(matched), func(i int) bool {
+			return q.after(matched[i], key, slug)
+		})
+		q.Page = start/q.PerPage + 1
+	}
+	start = min(start, len(matched))
+	end := min(start+q.PerPage, len(matched))
+
+	list := PostList{
+		Posts:      make([]PostSummary, 0, end-start),
+		Total:      len(matched),
+		Page:       q.Page,
+		PerPage:    q.PerPage,
+		TotalPages: (len(matched) + q.PerPage - 1) / q.PerPage,
+	}
+	for _, page := range matched[start:end] {
+		list.Posts = append(list.Posts, summarizePost(page))
+	}
+	if end < len(matched) {
+		list.NextCursor = encodeCursor(q.sortKey(matched[end-1]), matched[end-1].Slug())
+	}
+	if start > 0 && start < len(matched) {
+		list.hasPrev = true
+		prev := max(start-q.PerPage, 0)
+		if prev > 0 {
+			list.PrevCursor = encodeCursor(q.sortKey(matched[prev-1]), matched[prev-1].Slug())
+		}
+	}
+
+	return list, nil
+}
+
+// LinkHeader builds an RFC 8288 Link header value for the list using
+// base as the request URL.
+func (l PostList) LinkHeader(base *url.URL, cursor bool) string {
+	link := func(rel string, set func(url.Values)) string {
+		u := *base
+		values := u.Query()
+		values.Del("cursor")
+		values.Del("page")
+		set(values)
+		u.RawQuery = values.Encode()
+		return fmt.Sprintf("<%s>; rel=\"%s\"", u.String(), rel)
+	}
+	page := func(n int) func(url.Values) {
+		return func(v url.Values) { v.Set("page", strconv.Itoa(n)) }
+	}
+
+	var links []string
+	if cursor {
+		if l.NextCursor != "" {
+			links = append(links, link("next", func(v url.Values) { v.Set("cursor", l.NextCursor) }))
+		}
+		if l.PrevCursor != "" {
+			links = append(links, link("prev", func(v url.Values) { v.Set("cursor", l.PrevCursor) }))
+		} else if l.hasPrev || l.Page > 1 {
+			links = append(links, link("prev", page(1)))
+		}
+	} else {
+		if l.Page < l.TotalPages {
+			links = append(links, link("next", page(l.Page+1)))
+		}
+		if l.Page > 1 {
+			links = append(links, link("prev", page(min(l.Page-1, max(l.TotalPages, 1)))))
+		}
+	}
+	if l.TotalPages > 0 {
+		links = append(links, link("first", page(1)))
+		links = append(links, link("last", page(l.TotalPages)))
+	}
+	return strings.Join(links, ", ")
+}
+
+func (q PostQuery) matches(page *Page) bool {
+	draft := page.Draft()
+	switch q.Draft {
+	case DraftOnly:
+		if !draft {
+			return false
+		}
+	case DraftAny:
+	default:
+		if draft {
+			return false
+		}
+	}
+	if date := page.Front().Date; q.Year != 0 && (date.IsZero() || date.Year() != q.Year) {
+		return false
+	}
+	if q.Tag != "" && !slices.ContainsFunc(page.Tags(), func(tag string) bool {
+		return strings.EqualFold(tag, q.Tag)
+	}) {
+		return false
+	}
+	return true
+}
+
+func (q PostQuery) sortKey(page *Page) string {
+	if q.Sort == SortTitle {
+		return strings.ToLower(page.Title())
+	}
+	date := page.Front().Date
+	if date.IsZero() {
+		return ""
+	}
+	return date.UTC().Format(time.RFC3339)
+}
+
+// less orders posts by sort key with the slug as a tie breaker so the
+// order is total and cursors are stable.
+func (q PostQuery) less(a, b *Page) bool {
+	return q.compare(q.sortKey(a), a.Slug(), q.sortKey(b), b.Slug()) < 0
+}
+
+// after reports whether page sorts after the cursor position.
+func (q PostQuery) after(page *Page, key, slug string) bool {
+	return q.compare(q.sortKey(page), page.Slug(), key, slug) > 0
+}
+
+func (q PostQuery) compare(keyA, slugA, keyB, slugB string) int {
+	// Undated posts have no key and come last in either order.
+	if (keyA == "") != (keyB == "") {
+		if keyA == "" {
+			return 1
+		}
+		return -1
+	}
+	c := strings.Compare(keyA, keyB)
+	if c == 0 {
+		c = strings.Compare(slugA, slugB)
+	}
+	if q.Desc {
+		return -c
+	}
+	return c
+}
+
+func encodeCursor(key, slug string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key + "\x00" + slug))
+}
+
+func decodeCursor(cursor string) (string, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", ErrInvalidCursor
+	}
+	key, slug, ok := strings.Cut(string(raw), "\x00")
+	if !ok {
+		return "", "", ErrInvalidCursor
+	}
+	return key, slug, nil
+}
+
+func summarizePost(page *Page) PostSummary {
+	return PostSummary{
+		Title:       page.Title(),
+		Slug:        page.Slug(),
+		URL:         page.URL(),
+		Date:        page.Front().Date,
+		Description: page.Description(),
+		Tags:        page.Tags(),
+		ReadingTime: page.ReadingTime(),
+		Draft:       page.Draft(),
+	}
+}
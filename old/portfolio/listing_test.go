@@ -0,0 +1,224 @@
+package portfolio
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPosts() []*Page {
+	post := func(slug, title, date string, draft bool, tags ...string) *Page {
+		return &Page{
+			Path:    "blog/" + slug + ".md",
+			Content: []byte("<p>" + strings.Repeat("word ", 450) + "</p>"),
+			Meta: map[string]any{
+				"title": title,
+				"slug":  slug,
+				"date":  date,
+				"draft": draft,
+				"tags":  tags,
+			},
+		}
+	}
+	return []*Page{
+		post("alpha", "Alpha", "2025-01-06", false, "go", "web"),
+		post("bravo", "Bravo", "2025-03-01", false, "php"),
+		post("charlie", "Charlie", "2024-12-21", false, "Go"),
+		post("delta", "Delta", "2026-02-11", false, "go"),
+		post("echo", "Echo", "2025-06-01", true, "go"),
+	}
+}
+
+func slugs(list PostList) []string {
+	var out []string
+	for _, post := range list.Posts {
+		out = append(out, post.Slug)
+	}
+	return out
+}
+
+func TestParsePostQuery(t *testing.T) {
+	q, err := ParsePostQuery(url.Values{})
+	require.NoError(t, err)
+	assert.Equal(t, SortDate, q.Sort)
+	assert.True(t, q.Desc)
+	assert.Equal(t, DraftExclude, q.Draft)
+	assert.Equal(t, 1, q.Page)
+	assert.Equal(t, DefaultPerPage, q.PerPage)
+
+	q, err = ParsePostQuery(url.Values{"sort": {"title"}, "per_page": {"500"}})
+	require.NoError(t, err)
+	assert.False(t, q.Desc)
+	assert.Equal(t, MaxPerPage, q.PerPage)
+
+	for _, bad := range []url.Values{
+		{"year": {"abc"}},
+		{"draft": {"maybe"}},
+		{"sort": {"author"}},
+		{"order": {"sideways"}},
+		{"page": {"0"}},
+		{"per_page": {"-1"}},
+	} {
+		_, err := ParsePostQuery(bad)
+		assert.Error(t, err, bad.Encode())
+	}
+}
+
+func TestListPostsFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{name: "default excludes drafts", query: "", expected: []string{"delta", "bravo", "alpha", "charlie"}},
+		{name: "tag is case insensitive", query: "tag=GO", expected: []string{"delta", "alpha", "charlie"}},
+		{name: "year", query: "year=2025", expected: []string{"bravo", "alpha"}},
+		{name: "drafts only", query: "draft=true", expected: []string{"echo"}},
+		{name: "any draft state", query: "draft=any&year=2025", expected: []string{"echo", "bravo", "alpha"}},
+		{name: "title sort", query: "sort=title", expected: []string{"alpha", "bravo", "charlie", "delta"}},
+		{name: "oldest first", query: "order=asc", expected: []string{"charlie", "alpha", "bravo", "delta"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+			q, err := ParsePostQuery(values)
+			require.NoError(t, err)
+
+			list, err := ListPosts(testPosts(), q)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, slugs(list))
+			assert.Equal(t, len(tt.expected), list.Total)
+		})
+	}
+}
+
+func TestListPostsUndated(t *testing.T) {
+	undated := &Page{Path: "blog/undated.md", Meta: map[string]any{"title": "Undated", "slug": "undated"}}
+	pages := append(testPosts(), undated)
+
+	// Undated posts come last in either order and are in no year.
+	for _, desc := range []bool{true, false} {
+		list, err := ListPosts(pages, PostQuery{Sort: SortDate, Desc: desc})
+		require.NoError(t, err)
+		assert.Equal(t, "undated", list.Posts[len(list.Posts)-1].Slug)
+		assert.True(t, list.Posts[len(list.Posts)-1].Date.IsZero())
+	}
+	list, err := ListPosts(pages, PostQuery{Year: time.Now().Year()})
+	require.NoError(t, err)
+	assert.NotContains(t, slugs(list), "undated")
+
+	// Cursors of undated posts do not depend on the time of the request.
+	zulu := &Page{Path: "blog/zulu.md", Meta: map[string]any{"title": "Zulu", "slug": "zulu"}}
+	q := PostQuery{Sort: SortDate, Desc: true, PerPage: 5}
+	first, err := ListPosts(append(pages, zulu), q)
+	require.NoError(t, err)
+	key, slug, err := decodeCursor(first.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "zulu"}, []string{key, slug})
+
+	q.Cursor = first.NextCursor
+	rest, err := ListPosts(append(pages, zulu), q)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"undated"}, slugs(rest))
+}
+
+func TestHandleListPostsDrafts(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), nil)
+	for _, draft := range []string{"true", "any"} {
+		req := httptest.NewRequest("GET", "/posts?draft="+draft, nil)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code, draft)
+	}
+
+	config := DefaultServerConfig()
+	config.Preview = true
+	server = NewServer(config, nil)
+	req := httptest.NewRequest("GET", "/posts?draft=true", nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestListPostsPageNumbers(t *testing.T) {
+	q := PostQuery{Sort: SortTitle, Page: 2, PerPage: 3}
+	list, err := ListPosts(testPosts(), q)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"delta"}, slugs(list))
+	assert.Equal(t, 2, list.TotalPages)
+	assert.Equal(t, 3, list.Posts[0].ReadingTime)
+
+	base, _ := url.Parse("/posts?sort=title&per_page=3&page=2")
+	link := list.LinkHeader(base, false)
+	assert.Contains(t, link, `</posts?page=1&per_page=3&sort=title>; rel="prev"`)
+	assert.Contains(t, link, `</posts?page=2&per_page=3&sort=title>; rel="last"`)
+	assert.NotContains(t, link, `rel="next"`)
+
+	q.Page = 9
+	list, err = ListPosts(testPosts(), q)
+	require.NoError(t, err)
+	assert.Empty(t, list.Posts)
+}
+
+func TestListPostsCursor(t *testing.T) {
+	q := PostQuery{Sort: SortDate, Desc: true, PerPage: 2}
+
+	var seen []string
+	for range 3 {
+		list, err := ListPosts(testPosts(), q)
+		require.NoError(t, err)
+		seen = append(seen, slugs(list)...)
+		if list.NextCursor == "" {
+			break
+		}
+		q.Cursor = list.NextCursor
+	}
+	assert.Equal(t, []string{"delta", "bravo", "alpha", "charlie"}, seen)
+
+	_, err := ListPosts(testPosts(), PostQuery{Cursor: "!!"})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestListPostsCursorLinks(t *testing.T) {
+	q := PostQuery{Sort: SortTitle, PerPage: 1}
+	first, err := ListPosts(testPosts(), q)
+	require.NoError(t, err)
+
+	q.Cursor = first.NextCursor
+	second, err := ListPosts(testPosts(), q)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bravo"}, slugs(second))
+
+	base, _ := url.Parse("/posts?per_page=1&sort=title")
+	link := second.LinkHeader(base, true)
+	assert.Contains(t, link, fmt.Sprintf(`cursor=%s`, second.NextCursor))
+	assert.Contains(t, link, `</posts?page=1&per_page=1&sort=title>; rel="prev"`)
+}
+
+func TestListPostsCursorWithinFirstPage(t *testing.T) {
+	q := PostQuery{Sort: SortTitle, PerPage: 1}
+	first, err := ListPosts(testPosts(), q)
+	require.NoError(t, err)
+
+	// A cursor one post in, with pages of three, lands inside the first
+	// page: the previous posts are reached from the first page.
+	q.Cursor = first.NextCursor
+	q.PerPage = 3
+	list, err := ListPosts(testPosts(), q)
+	require.NoError(t, err)
+	assert.Equal(t, 1, list.Page)
+	assert.Empty(t, list.PrevCursor)
+
+	base, _ := url.Parse("/posts?per_page=3&sort=title")
+	link := list.LinkHeader(base, true)
+	assert.Contains(t, link, `</posts?page=1&per_page=3&sort=title>; rel="prev"`)
+}
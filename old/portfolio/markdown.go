@@ -7,7 +7,15 @@ import (
 )
 
 var Markdown goldmark.Markdown = goldmark.New(
-	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithExtensions(
+		extension.GFM,
+		extension.Footnote,
+		extension.DefinitionList,
+		highlighter,
+		mathml,
+		mermaid,
+		admonitions,
+	),
 	goldmark.WithParserOptions(
 		parser.WithAutoHeadingID(),
 		parser.WithAttribute(),
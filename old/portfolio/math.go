@@ -0,0 +1,322 @@
+package portfolio
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// KindMathInline is the node kind of MathInline.
+var KindMathInline = ast.NewNodeKind("MathInline")
+
+// MathInline is $...$ math within a paragraph. $$...$$ on part of a
+// line is display math that stays inline with the text.
+type MathInline struct {
+	ast.BaseInline
+	Display bool
+	// Source is the segment of the LaTeX between the delimiters.
+	Source text.Segment
+}
+
+// Kind implements ast.Node.
+func (n *MathInline) Kind() ast.NodeKind {
+	return KindMathInline
+}
+
+// Dump implements ast.Node.
+func (n *MathInline) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"TeX": string(n.Source.Value(source)),
+	}, nil)
+}
+
+// KindMathBlock is the node kind of MathBlock.
+var KindMathBlock = ast.NewNodeKind("MathBlock")
+
+// MathBlock is display math in a $$ block or a fenced math code block.
+// Its lines hold the LaTeX.
+type MathBlock struct {
+	ast.BaseBlock
+	// closed is set when the block opened and closed on one line.
+	closed bool
+}
+
+// Kind implements ast.Node.
+func (n *MathBlock) Kind() ast.NodeKind {
+	return KindMathBlock
+}
+
+// IsRaw implements ast.Node.
+func (n *MathBlock) IsRaw() bool {
+	return true
+}
+
+// Dump implements ast.Node.
+func (n *MathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// TeX returns the LaTeX of the block.
+func (n *MathBlock) TeX(source []byte) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < n.Lines().Len(); i++ {
+		line := n.Lines().At(i)
+		buf.Write(line.Value(source))
+	}
+	return buf.Bytes()
+}
+
+type mathInlineParser struct{}
+
+func (mathInlineParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+// Parse reads $...$ or $$...$$ on the current line. Like pandoc, the
+// opening $ must not be followed by a space and the closing $ must not
+// follow a space or precede a digit, so prices such as $5 and $10 stay
+// text.
+func (mathInlineParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, segment := block.PeekLine()
+	delim := 1
+	if len(line) > 1 && line[1] == '$' {
+		delim = 2
+	}
+	body := line[delim:]
+	if len(body) == 0 || util.IsSpace(body[0]) || body[0] == '$' {
+		return nil
+	}
+
+	end := -1
+	for i := 1; i < len(body) && end < 0; i++ {
+		switch {
+		case body[i] == '\\':
+			i++
+		case body[i] != '$':
+		case delim == 2:
+			if i+1 < len(body) && body[i+1] == '$' {
+				end = i
+			}
+		case !util.IsSpace(body[i-1]) && (i+1 == len(body) || !isDigit(body[i+1])):
+			end = i
+		}
+	}
+	if end < 0 {
+		return nil
+	}
+
+	start := segment.Start + delim
+	block.Advance(delim + end + delim)
+	return &MathInline{
+		Display: delim == 2,
+		Source:  text.NewSegment(start, start+end),
+	}
+}
+
+type mathBlockParser struct{}
+
+func (mathBlockParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+// Open starts a block at a line beginning with $$. The LaTeX may start
+// on the same line and the block may close on it too, but only at the
+// end of the line: "$$E=mc^2$$ is famous." is inline math in a
+// paragraph.
+func (mathBlockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || !bytes.HasPrefix(line[pos:], []byte("$$")) {
+		return nil, parser.NoChildren
+	}
+
+	node := &MathBlock{}
+	start := pos + 2
+	rest := util.TrimRightSpace(line[start:])
+	stop := start + len(rest)
+	switch closing := bytes.Index(rest, []byte("$$")); {
+	case closing < 0:
+	case closing == len(rest)-2:
+		node.closed = true
+		stop -= 2
+	default:
+		return nil, parser.NoChildren
+	}
+	if content := text.NewSegment(segment.Start+start-segment.Padding, segment.Start+stop-segment.Padding); !util.IsBlank(content.Value(reader.Source())) {
+		content.ForceNewline = true
+		node.Lines().Append(content)
+	}
+	reader.AdvanceToEOL()
+	return node, parser.NoChildren
+}
+
+// Continue adds lines until one ending with $$.
+func (mathBlockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	if node.(*MathBlock).closed {
+		return parser.Close
+	}
+	line, segment := reader.PeekLine()
+	rest := util.TrimRightSpace(line)
+	closing := bytes.HasSuffix(rest, []byte("$$"))
+	stop := len(line)
+	if closing {
+		stop = len(rest) - 2
+	}
+	content := text.NewSegment(segment.Start, segment.Start+stop)
+	if !closing || !util.IsBlank(content.Value(reader.Source())) {
+		content.ForceNewline = true
+		node.Lines().Append(content)
+	}
+	reader.AdvanceToEOL()
+	if closing {
+		return parser.Close
+	}
+	return parser.Continue | parser.NoChildren
+}
+
+func (mathBlockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (mathBlockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (mathBlockParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// mathFenceTransformer turns fenced code blocks with the math language
+// into math blocks.
+type mathFenceTransformer struct{}
+
+func (mathFenceTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	var fences []*ast.FencedCodeBlock
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if fence, ok := n.(*ast.FencedCodeBlock); ok && entering {
+			if string(fence.Language(reader.Source())) == "math" {
+				fences = append(fences, fence)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	for _, fence := range fences {
+		block := &MathBlock{}
+		block.SetLines(fence.Lines())
+		fence.Parent().ReplaceChild(fence.Parent(), fence, block)
+	}
+}
+
+type mathRenderer struct{}
+
+func (mathRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindMathInline, renderMathInline)
+	reg.Register(KindMathBlock, renderMathBlock)
+}
+
+// renderMathInline writes the MathML of inline math. Problems in the
+// LaTeX are marked in place by LaTeXToMathML and reported by the math
+// lint rule, so the errors are not needed here.
+func renderMathInline(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*MathInline)
+		ml, _ := LaTeXToMathML(string(n.Source.Value(source)), n.Display)
+		w.WriteString(ml)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func renderMathBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		ml, _ := LaTeXToMathML(string(node.(*MathBlock).TeX(source)), true)
+		w.WriteString(ml)
+		w.WriteByte('\n')
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// mathProblem is a LaTeX problem at a line of a markdown source.
+type mathProblem struct {
+	line int
+	err  *MathError
+}
+
+// mathErrors converts the math in a markdown source and returns the
+// problems found, with lines counted from 1.
+func mathErrors(source []byte) []mathProblem {
+	var problems []mathProblem
+	doc := Markdown.Parser().Parse(text.NewReader(source))
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		var segments []text.Segment
+		display := true
+		switch n := n.(type) {
+		case *MathInline:
+			segments, display = []text.Segment{n.Source}, n.Display
+		case *MathBlock:
+			segments = n.Lines().Sliced(0, n.Lines().Len())
+		default:
+			return ast.WalkContinue, nil
+		}
+
+		var tex []byte
+		for _, seg := range segments {
+			tex = append(tex, seg.Value(source)...)
+		}
+		_, err := LaTeXToMathML(string(tex), display)
+		for _, e := range mathErrorList(err) {
+			// Map the offset in the LaTeX back to the source.
+			offset := segments[len(segments)-1].Stop
+			pos := e.Pos
+			for _, seg := range segments {
+				if n := len(seg.Value(source)); pos >= n {
+					pos -= n
+					continue
+				}
+				offset = seg.Start + pos
+				break
+			}
+			problems = append(problems, mathProblem{
+				line: bytes.Count(source[:offset], []byte("\n")) + 1,
+				err:  e,
+			})
+		}
+		return ast.WalkSkipChildren, nil
+	})
+	return problems
+}
+
+// mathErrorList flattens the joined errors of LaTeXToMathML.
+func mathErrorList(err error) []*MathError {
+	var out []*MathError
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			if mathErr, ok := e.(*MathError); ok {
+				out = append(out, mathErr)
+			}
+		}
+	}
+	return out
+}
+
+type mathExtension struct{}
+
+// mathml renders $...$, $$...$$ and fenced math code blocks as MathML
+// on the server, so pages do not need a math script.
+var mathml goldmark.Extender = mathExtension{}
+
+func (mathExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(mathBlockParser{}, 150)),
+		parser.WithInlineParsers(util.Prioritized(mathInlineParser{}, 150)),
+		parser.WithASTTransformers(util.Prioritized(mathFenceTransformer{}, 100)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(mathRenderer{}, 500),
+	))
+}
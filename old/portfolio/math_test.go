@@ -0,0 +1,111 @@
+package portfolio
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownMath(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "inline",
+			src:      "The formula $x^2$ is squared.\n",
+			contains: []string{"<p>The formula <math ", "<msup><mi>x</mi><mn>2</mn></msup>", "</math> is squared.</p>"},
+		},
+		{
+			name:     "prices",
+			src:      "It costs $5 and $10, or $ 20 $.\n",
+			contains: []string{"<p>It costs $5 and $10, or $ 20 $.</p>"},
+			excludes: []string{"<math"},
+		},
+		{
+			name:     "escaped",
+			src:      "Not \\$math$ here.\n",
+			contains: []string{"<p>Not $math$ here.</p>"},
+		},
+		{
+			name:     "inline display",
+			src:      "Sum $$\\sum_i i$$ inline.\n",
+			contains: []string{`<p>Sum <math xmlns="http://www.w3.org/1998/Math/MathML" display="block">`, "<munder>"},
+		},
+		{
+			name:     "block",
+			src:      joinLines("Before", "$$", `\frac{a}{b}`, "$$", "After"),
+			contains: []string{"<p>Before</p>\n<math ", `display="block"`, "<mfrac>", "</math>\n<p>After</p>"},
+		},
+		{
+			name:     "single line block",
+			src:      "$$ E = mc^2 $$\n\nAfter\n",
+			contains: []string{`display="block"`, "<mi>E</mi>", "<p>After</p>"},
+		},
+		{
+			name:     "block with content on delimiter lines",
+			src:      joinLines(`$$a +`, `b$$`),
+			contains: []string{"<mi>a</mi><mo>+</mo><mi>b</mi>"},
+		},
+		{
+			name: "display math starting a paragraph",
+			src:  joinLines(`$$E=mc^2$$ is famous.`, "", "After"),
+			contains: []string{
+				`<p><math xmlns="http://www.w3.org/1998/Math/MathML" display="block">`,
+				"</math> is famous.</p>",
+				"<p>After</p>",
+			},
+		},
+		{
+			name:     "fenced",
+			src:      joinLines("```math", `\begin{pmatrix} 1 & 0 \\ 0 & 1 \end{pmatrix}`, "```"),
+			contains: []string{`display="block"`, "<mtable>"},
+			excludes: []string{"<pre", "<code"},
+		},
+		{
+			name:     "code span",
+			src:      "Use `$x$` literally.\n",
+			contains: []string{"<code>$x$</code>"},
+			excludes: []string{"<math"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := renderMarkdown(t, tt.src)
+			for _, want := range tt.contains {
+				assert.Contains(t, out, want)
+			}
+			for _, unwanted := range tt.excludes {
+				assert.NotContains(t, out, unwanted)
+			}
+		})
+	}
+}
+
+func TestMathErrors(t *testing.T) {
+	src := joinLines(
+		"Fine $x$ and broken $\\foo$.",
+		"",
+		"$$",
+		`a \\`,
+		`\bar{b} + \baz`,
+		"$$",
+		"",
+		"```math",
+		`\qux`,
+		"```",
+	)
+	var got []string
+	for _, problem := range mathErrors([]byte(src)) {
+		got = append(got, fmt.Sprintf("%d: %s", problem.line, problem.err.Msg))
+	}
+	assert.Equal(t, []string{
+		`1: unsupported command \foo`,
+		`5: unsupported command \baz`,
+		`9: unsupported command \qux`,
+	}, got)
+}
@@ -0,0 +1,736 @@
+package portfolio
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MathError reports LaTeX that could not be converted to MathML.
+type MathError struct {
+	// Pos is the byte offset of the problem in the LaTeX source.
+	Pos int
+	Msg string
+}
+
+func (e *MathError) Error() string {
+	return fmt.Sprintf("offset %d: %s", e.Pos, e.Msg)
+}
+
+// LaTeXToMathML converts a LaTeX math expression to a MathML <math>
+// element. It covers the common subset used in posts: fractions,
+// roots, sub and superscripts, Greek letters, operators, big operators
+// with limits, accents, font commands, \left...\right and the matrix,
+// cases and aligned environments.
+//
+// Conversion does not stop at the first problem. Unsupported commands
+// are rendered as <merror> elements so the rest of the expression
+// still displays, and every problem is reported in the returned error
+// as a *MathError.
+func LaTeXToMathML(tex string, display bool) (string, error) {
+	p := &mathParser{src: tex, display: display}
+	items := p.parseExpr()
+
+	var b strings.Builder
+	b.WriteString(`<math xmlns="http://www.w3.org/1998/Math/MathML"`)
+	if display {
+		b.WriteString(` display="block"`)
+	}
+	b.WriteString("><semantics><mrow>")
+	b.WriteString(strings.Join(items, ""))
+	b.WriteString(`</mrow><annotation encoding="application/x-tex">`)
+	b.WriteString(html.EscapeString(strings.TrimSpace(tex)))
+	b.WriteString("</annotation></semantics></math>")
+	return b.String(), errors.Join(p.errs...)
+}
+
+// mathToken is a command such as \frac, a single character or a run of
+// digits.
+type mathToken struct {
+	text string
+	pos  int
+}
+
+func (t mathToken) command() bool {
+	return len(t.text) > 1 && t.text[0] == '\\'
+}
+
+// mathAtom is a converted element along with whether its scripts are
+// placed as limits above and below it.
+type mathAtom struct {
+	ml     string
+	limits bool
+}
+
+type mathParser struct {
+	src     string
+	pos     int
+	display bool
+	// variant is the mathvariant set by font commands such as \mathbf.
+	variant string
+	// stops are the tokens that end the expression being parsed, such
+	// as "}" inside a group or "&" inside a matrix.
+	stops []map[string]bool
+	errs  []error
+}
+
+func (p *mathParser) errorf(pos int, format string, args ...any) {
+	p.errs = append(p.errs, &MathError{Pos: pos, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (p *mathParser) skipSpace() {
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+		p.pos += size
+	}
+}
+
+// scan reads the next token. Digits are read as a whole number unless
+// single is set, as in x^23 where only the 2 is the superscript.
+func (p *mathParser) scan(single bool) mathToken {
+	p.skipSpace()
+	start := p.pos
+	if p.pos >= len(p.src) {
+		return mathToken{pos: start}
+	}
+
+	c := p.src[p.pos]
+	switch {
+	case c == '\\':
+		p.pos++
+		if p.pos >= len(p.src) {
+			return mathToken{text: `\`, pos: start}
+		}
+		if !isASCIILetter(p.src[p.pos]) {
+			_, size := utf8.DecodeRuneInString(p.src[p.pos:])
+			p.pos += size
+			return mathToken{text: p.src[start:p.pos], pos: start}
+		}
+		for p.pos < len(p.src) && isASCIILetter(p.src[p.pos]) {
+			p.pos++
+		}
+		// \operatorname* and friends take a star.
+		if p.pos < len(p.src) && p.src[p.pos] == '*' {
+			p.pos++
+		}
+		return mathToken{text: p.src[start:p.pos], pos: start}
+	case c >= '0' && c <= '9' && !single:
+		dot := false
+		for p.pos < len(p.src) {
+			c := p.src[p.pos]
+			if c == '.' && !dot && p.pos+1 < len(p.src) && isDigit(p.src[p.pos+1]) {
+				dot = true
+			} else if !isDigit(c) {
+				break
+			}
+			p.pos++
+		}
+		return mathToken{text: p.src[start:p.pos], pos: start}
+	}
+	_, size := utf8.DecodeRuneInString(p.src[p.pos:])
+	p.pos += size
+	return mathToken{text: p.src[start:p.pos], pos: start}
+}
+
+func (p *mathParser) next() mathToken {
+	return p.scan(false)
+}
+
+func (p *mathParser) peek() mathToken {
+	pos := p.pos
+	tok := p.scan(false)
+	p.pos = pos
+	return tok
+}
+
+func (p *mathParser) stopped(tok mathToken) bool {
+	if tok.text == "" {
+		return true
+	}
+	for _, stops := range p.stops {
+		if stops[tok.text] {
+			return true
+		}
+	}
+	return false
+}
+
+// parseUntil parses an expression ending at one of the stop tokens,
+// which is left unread.
+func (p *mathParser) parseUntil(stops ...string) []string {
+	set := make(map[string]bool, len(stops))
+	for _, stop := range stops {
+		set[stop] = true
+	}
+	p.stops = append(p.stops, set)
+	defer func() { p.stops = p.stops[:len(p.stops)-1] }()
+	return p.parseExpr()
+}
+
+// parseExpr parses atoms with their scripts until a stop token.
+func (p *mathParser) parseExpr() []string {
+	var items []string
+	for !p.stopped(p.peek()) {
+		tok := p.peek()
+		switch tok.text {
+		case "}", "&", `\end`, `\right`:
+			// Not a stop for this expression, so it is unbalanced.
+			p.next()
+			p.errorf(tok.pos, "unexpected %s", tok.text)
+			continue
+		case `\displaystyle`, `\textstyle`:
+			p.next()
+			rest := p.parseExpr()
+			items = append(items, fmt.Sprintf(`<mstyle displaystyle="%t">%s</mstyle>`,
+				tok.text == `\displaystyle`, mathRow(rest)))
+			return items
+		}
+		items = append(items, p.parseScripts(p.parseAtom()))
+	}
+	return items
+}
+
+// parseScripts attaches the sub and superscripts that follow base.
+func (p *mathParser) parseScripts(base mathAtom) string {
+	var sub, sup string
+	var primes int
+	limits := base.limits && p.display
+	for {
+		tok := p.peek()
+		switch tok.text {
+		case `\limits`, `\nolimits`:
+			p.next()
+			limits = tok.text == `\limits`
+			continue
+		case "'":
+			p.next()
+			primes++
+			continue
+		case "^", "_":
+			p.next()
+			arg := p.parseArg()
+			target := &sup
+			if tok.text == "_" {
+				target = &sub
+			}
+			if *target != "" {
+				p.errorf(tok.pos, "double %s", tok.text)
+			}
+			*target = arg
+			continue
+		}
+		break
+	}
+
+	if primes > 0 {
+		prime := "<mo>" + strings.Repeat("′", primes) + "</mo>"
+		if sup == "" {
+			sup = prime
+		} else {
+			sup = "<mrow>" + prime + sup + "</mrow>"
+		}
+	}
+
+	under, over, both := "msub", "msup", "msubsup"
+	if limits {
+		under, over, both = "munder", "mover", "munderover"
+	}
+	switch {
+	case sub != "" && sup != "":
+		return fmt.Sprintf("<%s>%s%s%s</%s>", both, base.ml, sub, sup, both)
+	case sub != "":
+		return fmt.Sprintf("<%s>%s%s</%s>", under, base.ml, sub, under)
+	case sup != "":
+		return fmt.Sprintf("<%s>%s%s</%s>", over, base.ml, sup, over)
+	}
+	return base.ml
+}
+
+// parseArg parses a command argument: a braced group or a single token.
+func (p *mathParser) parseArg() string {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		p.errorf(p.pos, "missing argument")
+		return "<mrow></mrow>"
+	}
+	if p.src[p.pos] == '{' {
+		return p.parseGroup()
+	}
+	pos := p.pos
+	tok := p.scan(true)
+	p.pos = pos
+	if p.stopped(tok) || tok.text == "^" || tok.text == "_" {
+		p.errorf(pos, "missing argument")
+		return "<mrow></mrow>"
+	}
+	return p.parseSingle()
+}
+
+// parseSingle parses one token without reading digits beyond the first.
+func (p *mathParser) parseSingle() string {
+	pos := p.pos
+	tok := p.scan(true)
+	if isDigit(tok.text[0]) {
+		return p.number(tok.text)
+	}
+	p.pos = pos
+	return p.parseAtom().ml
+}
+
+// parseGroup parses a braced group as a single row.
+func (p *mathParser) parseGroup() string {
+	open := p.next()
+	items := p.parseUntil("}")
+	if p.peek().text != "}" {
+		p.errorf(open.pos, "missing closing brace")
+	} else {
+		p.next()
+	}
+	return "<mrow>" + strings.Join(items, "") + "</mrow>"
+}
+
+// rawGroup reads a braced group as text, as used by \text and
+// \begin.
+func (p *mathParser) rawGroup() (string, bool) {
+	p.skipSpace()
+	if p.pos >= len(p.src) || p.src[p.pos] != '{' {
+		p.errorf(p.pos, "missing argument")
+		return "", false
+	}
+	start := p.pos
+	depth := 0
+	for i := p.pos; i < len(p.src); i++ {
+		switch p.src[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				p.pos = i + 1
+				return p.src[start+1 : i], true
+			}
+		}
+	}
+	p.errorf(start, "missing closing brace")
+	p.pos = len(p.src)
+	return p.src[start+1:], true
+}
+
+func (p *mathParser) parseAtom() mathAtom {
+	tok := p.next()
+	t := tok.text
+	switch {
+	case t == "":
+		p.errorf(tok.pos, "missing argument")
+		return mathAtom{ml: "<mrow></mrow>"}
+	case t == "{":
+		p.pos = tok.pos
+		return mathAtom{ml: p.parseGroup()}
+	case t == "^" || t == "_" || t == "'":
+		// A script without a base attaches to an empty row.
+		p.pos = tok.pos
+		return mathAtom{ml: "<mrow></mrow>"}
+	case tok.command():
+		return p.parseCommand(tok)
+	case isDigit(t[0]):
+		return mathAtom{ml: p.number(t)}
+	case t == "~":
+		return mathAtom{ml: `<mspace width="0.3333em"></mspace>`}
+	}
+
+	r, _ := utf8.DecodeRuneInString(t)
+	if unicode.IsLetter(r) {
+		return mathAtom{ml: p.identifier(t)}
+	}
+	if op, ok := mathCharOps[t]; ok {
+		return mathAtom{ml: op}
+	}
+	return mathAtom{ml: "<mo>" + html.EscapeString(t) + "</mo>"}
+}
+
+func (p *mathParser) identifier(name string) string {
+	if p.variant != "" {
+		return fmt.Sprintf(`<mi mathvariant="%s">%s</mi>`, p.variant, html.EscapeString(name))
+	}
+	return "<mi>" + html.EscapeString(name) + "</mi>"
+}
+
+func (p *mathParser) number(n string) string {
+	if p.variant != "" && p.variant != "normal" {
+		return fmt.Sprintf(`<mn mathvariant="%s">%s</mn>`, p.variant, n)
+	}
+	return "<mn>" + n + "</mn>"
+}
+
+func (p *mathParser) parseCommand(tok mathToken) mathAtom {
+	name := tok.text[1:]
+
+	if letter, ok := mathGreek[name]; ok {
+		if unicode.IsUpper([]rune(letter)[0]) && p.variant == "" {
+			return mathAtom{ml: `<mi mathvariant="normal">` + letter + "</mi>"}
+		}
+		return mathAtom{ml: p.identifier(letter)}
+	}
+	if sym, ok := mathIdentifiers[name]; ok {
+		return mathAtom{ml: "<mi>" + sym + "</mi>"}
+	}
+	if op, ok := mathOperators[name]; ok {
+		return mathAtom{ml: "<mo>" + html.EscapeString(op) + "</mo>"}
+	}
+	if op, ok := mathLargeOps[name]; ok {
+		return mathAtom{
+			ml:     `<mo largeop="true" movablelimits="true">` + op + "</mo>",
+			limits: !strings.Contains("∫∬∭∮", op),
+		}
+	}
+	if limits, ok := mathFunctions[name]; ok {
+		return mathAtom{ml: "<mi>" + name + "</mi>", limits: limits}
+	}
+	if width, ok := mathSpaces[name]; ok {
+		return mathAtom{ml: `<mspace width="` + width + `"></mspace>`}
+	}
+	if variant, ok := mathVariants[name]; ok {
+		saved := p.variant
+		p.variant = variant
+		arg := p.parseArg()
+		p.variant = saved
+		return mathAtom{ml: arg}
+	}
+	if accent, ok := mathAccents[name]; ok {
+		arg := p.parseArg()
+		if name == "underline" || name == "underbrace" {
+			return mathAtom{
+				ml:     fmt.Sprintf(`<munder accentunder="true">%s<mo stretchy="true">%s</mo></munder>`, arg, accent),
+				limits: name == "underbrace",
+			}
+		}
+		return mathAtom{
+			ml:     fmt.Sprintf(`<mover accent="true">%s<mo stretchy="%t">%s</mo></mover>`, arg, mathStretchyAccents[name], accent),
+			limits: name == "overbrace",
+		}
+	}
+
+	switch name {
+	case "frac", "dfrac", "tfrac", "cfrac":
+		num := p.parseArg()
+		den := p.parseArg()
+		return mathAtom{ml: "<mfrac>" + num + den + "</mfrac>"}
+	case "binom":
+		top := p.parseArg()
+		bottom := p.parseArg()
+		return mathAtom{ml: `<mrow><mo>(</mo><mfrac linethickness="0">` + top + bottom + `</mfrac><mo>)</mo></mrow>`}
+	case "sqrt":
+		p.skipSpace()
+		if p.pos < len(p.src) && p.src[p.pos] == '[' {
+			open := p.next()
+			index := p.parseUntil("]")
+			if p.peek().text != "]" {
+				p.errorf(open.pos, "missing ] in \\sqrt")
+			} else {
+				p.next()
+			}
+			body := p.parseArg()
+			return mathAtom{ml: "<mroot>" + body + mathRow(index) + "</mroot>"}
+		}
+		return mathAtom{ml: "<msqrt>" + p.parseArg() + "</msqrt>"}
+	case "text", "textrm", "textnormal", "mbox", "textbf", "textit", "texttt":
+		text, _ := p.rawGroup()
+		variant := map[string]string{"textbf": "bold", "textit": "italic", "texttt": "monospace"}[name]
+		if variant != "" {
+			return mathAtom{ml: fmt.Sprintf(`<mtext mathvariant="%s">%s</mtext>`, variant, html.EscapeString(text))}
+		}
+		return mathAtom{ml: "<mtext>" + html.EscapeString(text) + "</mtext>"}
+	case "operatorname", "operatorname*":
+		text, _ := p.rawGroup()
+		return mathAtom{
+			ml:     "<mi>" + html.EscapeString(strings.TrimSpace(text)) + "</mi>",
+			limits: name == "operatorname*",
+		}
+	case "left":
+		return mathAtom{ml: p.parseLeft(tok)}
+	case "middle":
+		return mathAtom{ml: p.delimiter(p.next(), true)}
+	case "big", "Big", "bigg", "Bigg", "bigl", "bigr", "Bigl", "Bigr", "biggl", "biggr", "Biggl", "Biggr":
+		return mathAtom{ml: p.delimiter(p.next(), false)}
+	case "not":
+		next := p.parseAtom().ml
+		if strings.HasPrefix(next, "<mo>") {
+			return mathAtom{ml: strings.Replace(next, "</mo>", "̸</mo>", 1)}
+		}
+		return mathAtom{ml: "<mo>/</mo>" + next}
+	case "pmod":
+		arg := p.parseArg()
+		return mathAtom{ml: `<mrow><mspace width="1em"></mspace><mo>(</mo><mi>mod</mi><mspace width="0.3333em"></mspace>` + arg + `<mo>)</mo></mrow>`}
+	case "begin":
+		return mathAtom{ml: p.parseEnvironment(tok)}
+	case `\`:
+		if p.display {
+			return mathAtom{ml: `<mspace linebreak="newline"></mspace>`}
+		}
+		return mathAtom{}
+	}
+
+	p.errorf(tok.pos, "unsupported command %s", tok.text)
+	return mathAtom{ml: "<merror><mtext>" + html.EscapeString(tok.text) + "</mtext></merror>"}
+}
+
+// delimiter converts the token following \left, \right, \middle or
+// \big to an operator. "." is an empty delimiter.
+func (p *mathParser) delimiter(tok mathToken, stretchy bool) string {
+	if tok.text == "." {
+		return ""
+	}
+	d, ok := mathDelimiters[tok.text]
+	if !ok {
+		if tok.text == "" {
+			p.errorf(tok.pos, "missing delimiter")
+		} else {
+			p.errorf(tok.pos, "invalid delimiter %s", tok.text)
+		}
+		return ""
+	}
+	return fmt.Sprintf(`<mo fence="true" stretchy="%t">%s</mo>`, stretchy, html.EscapeString(d))
+}
+
+func (p *mathParser) parseLeft(left mathToken) string {
+	open := p.delimiter(p.next(), true)
+	body := p.parseUntil(`\right`)
+	close := ""
+	if p.peek().text != `\right` {
+		p.errorf(left.pos, `\left without \right`)
+	} else {
+		p.next()
+		close = p.delimiter(p.next(), true)
+	}
+	return "<mrow>" + open + strings.Join(body, "") + close + "</mrow>"
+}
+
+// parseEnvironment converts the matrix like environments to a table.
+func (p *mathParser) parseEnvironment(begin mathToken) string {
+	env, _ := p.rawGroup()
+	style, ok := mathEnvironments[env]
+	if !ok {
+		p.errorf(begin.pos, "unsupported environment %s", env)
+	}
+	align := style.align
+	if env == "array" {
+		spec, _ := p.rawGroup()
+		var cols []string
+		for _, c := range spec {
+			switch c {
+			case 'l':
+				cols = append(cols, "left")
+			case 'c':
+				cols = append(cols, "center")
+			case 'r':
+				cols = append(cols, "right")
+			}
+		}
+		align = strings.Join(cols, " ")
+	}
+
+	var rows [][]string
+	var row []string
+	for {
+		cell := p.parseUntil("&", `\\`, `\end`)
+		row = append(row, mathRow(cell))
+		tok := p.next()
+		switch tok.text {
+		case "&":
+			continue
+		case `\\`:
+			rows = append(rows, row)
+			row = nil
+			continue
+		case `\end`:
+			if end, _ := p.rawGroup(); end != env {
+				p.errorf(tok.pos, `\begin{%s} ended by \end{%s}`, env, end)
+			}
+		default:
+			p.errorf(begin.pos, `\begin{%s} without \end`, env)
+		}
+		break
+	}
+	// A trailing \\ does not start a new row.
+	if len(row) > 1 || row[0] != "<mrow></mrow>" {
+		rows = append(rows, row)
+	}
+
+	var b strings.Builder
+	b.WriteString("<mrow>")
+	if style.open != "" {
+		fmt.Fprintf(&b, `<mo fence="true" stretchy="true">%s</mo>`, style.open)
+	}
+	b.WriteString("<mtable")
+	if align != "" {
+		fmt.Fprintf(&b, ` columnalign="%s"`, align)
+	}
+	if style.display {
+		b.WriteString(` displaystyle="true"`)
+	}
+	b.WriteString(">")
+	for _, row := range rows {
+		b.WriteString("<mtr>")
+		for _, cell := range row {
+			b.WriteString("<mtd>" + cell + "</mtd>")
+		}
+		b.WriteString("</mtr>")
+	}
+	b.WriteString("</mtable>")
+	if style.close != "" {
+		fmt.Fprintf(&b, `<mo fence="true" stretchy="true">%s</mo>`, style.close)
+	}
+	b.WriteString("</mrow>")
+	return b.String()
+}
+
+// mathRow wraps items in an mrow unless there is exactly one.
+func mathRow(items []string) string {
+	if len(items) == 1 {
+		return items[0]
+	}
+	return "<mrow>" + strings.Join(items, "") + "</mrow>"
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+var mathCharOps = map[string]string{
+	"-": "<mo>−</mo>",
+	"*": "<mo>∗</mo>",
+	"(": `<mo stretchy="false">(</mo>`,
+	")": `<mo stretchy="false">)</mo>`,
+	"[": `<mo stretchy="false">[</mo>`,
+	"]": `<mo stretchy="false">]</mo>`,
+	"|": `<mo stretchy="false">|</mo>`,
+	"<": "<mo>&lt;</mo>",
+	">": "<mo>&gt;</mo>",
+}
+
+var mathGreek = map[string]string{
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ϵ",
+	"varepsilon": "ε", "zeta": "ζ", "eta": "η", "theta": "θ", "vartheta": "ϑ",
+	"iota": "ι", "kappa": "κ", "lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ",
+	"omicron": "ο", "pi": "π", "varpi": "ϖ", "rho": "ρ", "varrho": "ϱ",
+	"sigma": "σ", "varsigma": "ς", "tau": "τ", "upsilon": "υ", "phi": "ϕ",
+	"varphi": "φ", "chi": "χ", "psi": "ψ", "omega": "ω",
+	"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ",
+	"Pi": "Π", "Sigma": "Σ", "Upsilon": "Υ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+}
+
+var mathIdentifiers = map[string]string{
+	"infty": "∞", "partial": "∂", "nabla": "∇", "emptyset": "∅",
+	"varnothing": "∅", "ell": "ℓ", "hbar": "ℏ", "Re": "ℜ", "Im": "ℑ",
+	"aleph": "ℵ", "imath": "ı", "jmath": "ȷ", "wp": "℘",
+}
+
+var mathOperators = map[string]string{
+	"pm": "±", "mp": "∓", "times": "×", "div": "÷", "cdot": "⋅", "ast": "∗",
+	"star": "⋆", "circ": "∘", "bullet": "∙", "oplus": "⊕", "otimes": "⊗",
+	"leq": "≤", "le": "≤", "geq": "≥", "ge": "≥", "neq": "≠", "ne": "≠",
+	"ll": "≪", "gg": "≫", "approx": "≈", "equiv": "≡", "sim": "∼",
+	"simeq": "≃", "cong": "≅", "propto": "∝", "in": "∈", "notin": "∉",
+	"ni": "∋", "subset": "⊂", "subseteq": "⊆", "supset": "⊃", "supseteq": "⊇",
+	"cup": "∪", "cap": "∩", "setminus": "∖", "wedge": "∧", "land": "∧",
+	"vee": "∨", "lor": "∨", "neg": "¬", "lnot": "¬", "forall": "∀",
+	"exists": "∃", "nexists": "∄", "to": "→", "rightarrow": "→",
+	"leftarrow": "←", "gets": "←", "Rightarrow": "⇒", "Leftarrow": "⇐",
+	"leftrightarrow": "↔", "Leftrightarrow": "⇔", "mapsto": "↦",
+	"implies": "⟹", "iff": "⟺", "uparrow": "↑", "downarrow": "↓",
+	"ldots": "…", "dots": "…", "cdots": "⋯", "vdots": "⋮", "ddots": "⋱",
+	"prime": "′", "angle": "∠", "perp": "⊥", "parallel": "∥", "mid": "∣",
+	"vert": "|", "Vert": "‖", "|": "‖", "langle": "⟨", "rangle": "⟩",
+	"lfloor": "⌊", "rfloor": "⌋", "lceil": "⌈", "rceil": "⌉",
+	"{": "{", "}": "}", "lbrace": "{", "rbrace": "}",
+	"%": "%", "$": "$", "&": "&", "#": "#", "_": "_",
+	"colon": ":", "bmod": "mod", "mod": "mod", "triangle": "△",
+	"therefore": "∴", "because": "∵", "top": "⊤", "bot": "⊥",
+}
+
+var mathLargeOps = map[string]string{
+	"sum": "∑", "prod": "∏", "coprod": "∐", "int": "∫", "iint": "∬",
+	"iiint": "∭", "oint": "∮", "bigcup": "⋃", "bigcap": "⋂",
+	"bigoplus": "⨁", "bigotimes": "⨂", "bigvee": "⋁", "bigwedge": "⋀",
+}
+
+// mathFunctions are the named functions, mapped to whether their
+// scripts are placed as limits.
+var mathFunctions = map[string]bool{
+	"sin": false, "cos": false, "tan": false, "cot": false, "sec": false,
+	"csc": false, "arcsin": false, "arccos": false, "arctan": false,
+	"sinh": false, "cosh": false, "tanh": false, "log": false, "ln": false,
+	"lg": false, "exp": false, "deg": false, "dim": false, "ker": false,
+	"arg": false, "hom": false,
+	"lim": true, "liminf": true, "limsup": true, "max": true, "min": true,
+	"sup": true, "inf": true, "det": true, "gcd": true, "Pr": true,
+	"argmax": true, "argmin": true,
+}
+
+var mathSpaces = map[string]string{
+	",": "0.1667em", ":": "0.2222em", ">": "0.2222em", ";": "0.2778em",
+	"!": "-0.1667em", " ": "0.3333em", "quad": "1em", "qquad": "2em",
+}
+
+var mathVariants = map[string]string{
+	"mathrm": "normal", "mathbf": "bold", "mathit": "italic",
+	"mathbb": "double-struck", "mathcal": "script", "mathscr": "script",
+	"mathfrak": "fraktur", "mathsf": "sans-serif", "mathtt": "monospace",
+	"boldsymbol": "bold-italic", "bm": "bold-italic",
+}
+
+var mathAccents = map[string]string{
+	"hat": "^", "widehat": "^", "bar": "¯", "overline": "‾", "vec": "→",
+	"overrightarrow": "→", "dot": "˙", "ddot": "¨", "tilde": "~",
+	"widetilde": "~", "check": "ˇ", "acute": "´", "grave": "`", "breve": "˘",
+	"underline": "_", "overbrace": "⏞", "underbrace": "⏟",
+}
+
+var mathStretchyAccents = map[string]bool{
+	"widehat": true, "widetilde": true, "overline": true,
+	"overrightarrow": true, "overbrace": true,
+}
+
+var mathDelimiters = map[string]string{
+	"(": "(", ")": ")", "[": "[", "]": "]", "|": "|", "/": "/",
+	`\{`: "{", `\}`: "}", `\lbrace`: "{", `\rbrace`: "}",
+	`\|`: "‖", `\vert`: "|", `\Vert`: "‖", `\langle`: "⟨", `\rangle`: "⟩",
+	`\lfloor`: "⌊", `\rfloor`: "⌋", `\lceil`: "⌈", `\rceil`: "⌉",
+	"<": "⟨", ">": "⟩",
+}
+
+// mathEnvironment describes how an environment is laid out.
+type mathEnvironment struct {
+	open, close string
+	align       string
+	display     bool
+}
+
+var mathEnvironments = map[string]mathEnvironment{
+	"matrix":      {},
+	"smallmatrix": {},
+	"array":       {},
+	"pmatrix":     {open: "(", close: ")"},
+	"bmatrix":     {open: "[", close: "]"},
+	"Bmatrix":     {open: "{", close: "}"},
+	"vmatrix":     {open: "|", close: "|"},
+	"Vmatrix":     {open: "‖", close: "‖"},
+	"cases":       {open: "{", align: "left left"},
+	"aligned":     {align: "right left", display: true},
+	"align":       {align: "right left", display: true},
+	"align*":      {align: "right left", display: true},
+	"split":       {align: "right left", display: true},
+	"gathered":    {display: true},
+}
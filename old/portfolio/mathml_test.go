@@ -0,0 +1,104 @@
+package portfolio
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLaTeXToMathML(t *testing.T) {
+	tests := []struct {
+		name string
+		tex  string
+		want string
+	}{
+		{"identifiers and numbers", `x + 3.14`, `<mi>x</mi><mo>+</mo><mn>3.14</mn>`},
+		{"minus", `a - b`, `<mi>a</mi><mo>−</mo><mi>b</mi>`},
+		{"fraction", `\frac{a}{b}`, `<mfrac><mrow><mi>a</mi></mrow><mrow><mi>b</mi></mrow></mfrac>`},
+		{"fraction shorthand", `\frac12`, `<mfrac><mn>1</mn><mn>2</mn></mfrac>`},
+		{"superscript digit", `x^23`, `<msup><mi>x</mi><mn>2</mn></msup><mn>3</mn>`},
+		{"sub and superscript", `x_i^2`, `<msubsup><mi>x</mi><mi>i</mi><mn>2</mn></msubsup>`},
+		{"prime", `f''(x)`, `<msup><mi>f</mi><mo>′′</mo></msup>`},
+		{"greek", `\alpha + \Omega`, `<mi>α</mi><mo>+</mo><mi mathvariant="normal">Ω</mi>`},
+		{"operators", `a \leq b \neq c`, `<mi>a</mi><mo>≤</mo><mi>b</mi><mo>≠</mo><mi>c</mi>`},
+		{"negation", `a \not\in B`, "<mo>∈\u0338</mo>"},
+		{"square root", `\sqrt{x}`, `<msqrt><mrow><mi>x</mi></mrow></msqrt>`},
+		{"nth root", `\sqrt[3]{x}`, `<mroot><mrow><mi>x</mi></mrow><mn>3</mn></mroot>`},
+		{"sum inline", `\sum_{i=1}^n`, `<msubsup><mo largeop="true" movablelimits="true">∑</mo>`},
+		{"integral", `\int_0^1`, `<msubsup><mo largeop="true" movablelimits="true">∫</mo><mn>0</mn><mn>1</mn></msubsup>`},
+		{"function", `\sin x`, `<mi>sin</mi><mi>x</mi>`},
+		{"text", `\text{if } x`, `<mtext>if </mtext><mi>x</mi>`},
+		{"font", `\mathbb{R}`, `<mi mathvariant="double-struck">R</mi>`},
+		{"accent", `\vec{v}`, `<mover accent="true"><mrow><mi>v</mi></mrow><mo stretchy="false">→</mo></mover>`},
+		{"left right", `\left( x \right)`, `<mrow><mo fence="true" stretchy="true">(</mo><mi>x</mi><mo fence="true" stretchy="true">)</mo></mrow>`},
+		{"empty delimiter", `\left. x \right|`, `<mrow><mi>x</mi><mo fence="true" stretchy="true">|</mo></mrow>`},
+		{"spacing", `a\,b\quad c`, `<mspace width="0.1667em"></mspace><mi>b</mi><mspace width="1em"></mspace>`},
+		{"escapes", `\{ x \} \% <`, `<mo>{</mo><mi>x</mi><mo>}</mo><mo>%</mo><mo>&lt;</mo>`},
+		{
+			"matrix",
+			`\begin{bmatrix} 1 & 2 \\ 3 & 4 \\ \end{bmatrix}`,
+			`<mrow><mo fence="true" stretchy="true">[</mo><mtable><mtr><mtd><mn>1</mn></mtd><mtd><mn>2</mn></mtd></mtr><mtr><mtd><mn>3</mn></mtd><mtd><mn>4</mn></mtd></mtr></mtable><mo fence="true" stretchy="true">]</mo></mrow>`,
+		},
+		{
+			"cases",
+			`\begin{cases} 1 & x > 0 \\ 0 & \text{otherwise} \end{cases}`,
+			`<mtable columnalign="left left"><mtr><mtd><mn>1</mn></mtd><mtd><mrow><mi>x</mi><mo>&gt;</mo><mn>0</mn></mrow></mtd></mtr><mtr><mtd><mn>0</mn></mtd><mtd><mtext>otherwise</mtext></mtd></mtr></mtable>`,
+		},
+		{"array", `\begin{array}{lr} a & b \end{array}`, `<mtable columnalign="left right">`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ml, err := LaTeXToMathML(tt.tex, false)
+			require.NoError(t, err)
+			assert.Contains(t, ml, tt.want)
+			assert.True(t, strings.HasPrefix(ml, `<math xmlns="http://www.w3.org/1998/Math/MathML"><semantics><mrow>`), ml)
+		})
+	}
+}
+
+func TestLaTeXToMathMLDisplay(t *testing.T) {
+	ml, err := LaTeXToMathML(`\sum_{i=1}^n i \\ \lim_{x \to 0} x`, true)
+	require.NoError(t, err)
+	assert.Contains(t, ml, `display="block"`)
+	assert.Contains(t, ml, `<munderover><mo largeop="true" movablelimits="true">∑</mo>`)
+	assert.Contains(t, ml, `<munder><mi>lim</mi>`)
+	assert.Contains(t, ml, `<mspace linebreak="newline"></mspace>`)
+	assert.Contains(t, ml, `<annotation encoding="application/x-tex">\sum_{i=1}^n i \\ \lim_{x \to 0} x</annotation>`)
+}
+
+func TestLaTeXToMathMLErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		tex    string
+		errors []string
+		output string
+	}{
+		{"unsupported command", `a + \foo{b}`, []string{`offset 4: unsupported command \foo`}, `<merror><mtext>\foo</mtext></merror><mrow><mi>b</mi></mrow>`},
+		{"unsupported environment", `\begin{tabular} a \end{tabular}`, []string{"offset 0: unsupported environment tabular"}, `<mtable>`},
+		{"missing brace", `\frac{a`, []string{"offset 5: missing closing brace", "offset 7: missing argument"}, `<mfrac>`},
+		{"stray brace", `a}b`, []string{"offset 1: unexpected }"}, `<mi>a</mi><mi>b</mi>`},
+		{"left without right", `\left( x`, []string{`offset 0: \left without \right`}, `<mi>x</mi>`},
+		{"double superscript", `x^1^2`, []string{"offset 3: double ^"}, `<msup>`},
+		{"mismatched end", `\begin{matrix} a \end{pmatrix}`, []string{`offset 17: \begin{matrix} ended by \end{pmatrix}`}, `<mtable>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ml, err := LaTeXToMathML(tt.tex, false)
+			require.Error(t, err)
+			var got []string
+			for _, e := range mathErrorList(err) {
+				got = append(got, e.Error())
+			}
+			assert.Equal(t, tt.errors, got)
+			var mathErr *MathError
+			assert.True(t, errors.As(err, &mathErr))
+			assert.Contains(t, ml, tt.output)
+			assert.True(t, strings.HasSuffix(ml, "</math>"))
+		})
+	}
+}
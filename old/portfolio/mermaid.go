@@ -0,0 +1,102 @@
+package portfolio
+
+import (
+	"bytes"
+	"html"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// KindMermaid is the node kind of Mermaid.
+var KindMermaid = ast.NewNodeKind("Mermaid")
+
+// Mermaid is a ```mermaid fenced code block holding a diagram that is
+// drawn in the browser by mermaid.js.
+type Mermaid struct {
+	ast.BaseBlock
+}
+
+// Kind implements ast.Node.
+func (n *Mermaid) Kind() ast.NodeKind {
+	return KindMermaid
+}
+
+// IsRaw implements ast.Node.
+func (n *Mermaid) IsRaw() bool {
+	return true
+}
+
+// Dump implements ast.Node.
+func (n *Mermaid) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// mermaidTransformer turns fenced code blocks with the mermaid language
+// into diagrams, ahead of syntax highlighting.
+type mermaidTransformer struct{}
+
+func (mermaidTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	var fences []*ast.FencedCodeBlock
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if fence, ok := n.(*ast.FencedCodeBlock); ok && entering {
+			if string(fence.Language(reader.Source())) == "mermaid" {
+				fences = append(fences, fence)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	for _, fence := range fences {
+		diagram := &Mermaid{}
+		diagram.SetLines(fence.Lines())
+		fence.Parent().ReplaceChild(fence.Parent(), fence, diagram)
+	}
+}
+
+type mermaidRenderer struct{}
+
+func (mermaidRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindMermaid, renderMermaid)
+}
+
+// renderMermaid writes the same markup as the Hugo theme's
+// render-codeblock-mermaid.html hook.
+func renderMermaid(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+	var diagram bytes.Buffer
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		diagram.Write(line.Value(source))
+	}
+	w.WriteString("<pre class=\"mermaid\">\n  ")
+	w.WriteString(html.EscapeString(string(bytes.TrimSuffix(diagram.Bytes(), []byte("\n")))))
+	w.WriteString("\n</pre>\n")
+	return ast.WalkSkipChildren, nil
+}
+
+// HasMermaid reports whether the rendered page has a mermaid diagram,
+// so templates only load mermaid.js where it is needed.
+func (p *Page) HasMermaid() bool {
+	return bytes.Contains(p.Content, []byte(`<pre class="mermaid">`))
+}
+
+type mermaidExtension struct{}
+
+// mermaid renders ```mermaid fences for mermaid.js.
+var mermaid goldmark.Extender = mermaidExtension{}
+
+func (mermaidExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(mermaidTransformer{}, 100),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(mermaidRenderer{}, 500),
+	))
+}
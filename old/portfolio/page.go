@@ -2,12 +2,13 @@ package portfolio
 
 import (
 	"bytes"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/text"
-	"gopkg.in/yaml.v3"
 )
 
 type Page struct {
@@ -15,45 +16,77 @@ type Page struct {
 	Type    string
 	Content []byte
 	Meta    map[string]any
+
+	// FrontMatter is the typed front matter. When nil it is decoded from
+	// Meta on demand.
+	FrontMatter *FrontMatter
+
+	// Section is the top level content directory, empty for root pages.
+	Section string
+	// Kind is one of KindHome, KindSection or KindPage.
+	Kind string
+	// Resources lists the non-page files of a leaf bundle relative to
+	// the bundle directory.
+	Resources []string
+	// RelPermalink is the site relative URL from the site permalink
+	// rules. See URL.
+	RelPermalink string
+
+	stats *pageStats
+	// refs are the ref and relref shortcodes in the content, whose URLs
+	// are filled into unresolved to give Content.
+	refs       []*pageRef
+	unresolved []byte
+}
+
+// Front returns the typed front matter of the page.
+func (p *Page) Front() FrontMatter {
+	if p.FrontMatter != nil {
+		return *p.FrontMatter
+	}
+	fm, _ := DecodeFrontMatter(p.Meta)
+	return fm
 }
 
 // Title returns the post title from metadata or the first h1 from
 // content.
 func (p *Page) Title() string {
-	if title, ok := p.Meta["title"].(string); ok && title != "" {
+	if title := p.Front().Title; title != "" {
 		return title
 	}
 	return p.extractFirstHeading()
 }
 
+// Slug returns the slug from metadata or derives it from the file name.
+// Page bundles use the bundle directory name.
+func (p *Page) Slug() string {
+	if slug := p.Front().Slug; slug != "" {
+		return slug
+	}
+	name := strings.TrimSuffix(path.Base(p.Path), path.Ext(p.Path))
+	if name == "index" {
+		return path.Base(path.Dir(p.Path))
+	}
+	return name
+}
+
 // Date returns the post date from metadata or current date.
 func (p *Page) Date() time.Time {
-	if dateStr, ok := p.Meta["date"].(string); ok && dateStr != "" {
-		if parsedDate, err := time.Parse("2006-01-02", dateStr); err == nil {
-			return time.Date(
-				parsedDate.Year(),
-				parsedDate.Month(),
-				parsedDate.Day(),
-				0, 0, 0, 0,
-				time.Local,
-			)
-		}
-	} else if date, ok := p.Meta["date"].(time.Time); ok {
-		return time.Date(
-			date.Year(),
-			date.Month(),
-			date.Day(),
-			0, 0, 0, 0,
-			time.Local,
-		)
+	if date := p.Front().Date; !date.IsZero() {
+		return date
 	}
 	return time.Now()
 }
 
+// Draft reports whether the page is marked as a draft.
+func (p *Page) Draft() bool {
+	return p.Front().Draft
+}
+
 // Description returns the post description from metadata or lead
 // paragraph.
 func (p *Page) Description() string {
-	if desc, ok := p.Meta["description"].(string); ok && desc != "" {
+	if desc := p.Front().Description; desc != "" {
 		return desc
 	}
 	return p.extractLeadParagraph()
@@ -61,36 +94,9 @@ func (p *Page) Description() string {
 
 // Tags returns the tags from metadata as a slice of strings.
 func (p *Page) Tags() []string {
-	// If already a slice of strings, return it
-	if tags, ok := p.Meta["tags"].([]string); ok {
+	if tags := p.Front().Tags; tags != nil {
 		return tags
 	}
-
-	// Handle []any from YAML unmarshaling
-	if tagsInterface, ok := p.Meta["tags"].([]any); ok {
-		tags := make([]string, 0, len(tagsInterface))
-		for _, tag := range tagsInterface {
-			if tagStr, ok := tag.(string); ok {
-				tags = append(tags, tagStr)
-			}
-		}
-		if len(tags) > 0 {
-			return tags
-		}
-	}
-
-	// Try as string and unmarshal
-	if tagsStr, ok := p.Meta["tags"].(string); ok {
-		if tagsStr == "" {
-			return []string{}
-		}
-		var tags []string
-		if err := yaml.Unmarshal([]byte(tagsStr), &tags); err != nil {
-			return []string{}
-		}
-		return tags
-	}
-
 	return []string{}
 }
 
@@ -0,0 +1,174 @@
+package portfolio
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Permalinks holds Hugo's permalink configuration. Page rules apply to
+// regular pages and Section rules to section list pages, both keyed by
+// section name.
+type Permalinks struct {
+	Page    map[string]string
+	Section map[string]string
+}
+
+// ParsePermalinks reads the permalinks block of a Hugo config. Both the
+// legacy flat form ("blog: /blog/:slug") and the page/section form are
+// understood; a page/section entry wins over a flat one.
+func ParsePermalinks(raw map[string]any) Permalinks {
+	p := Permalinks{
+		Page:    make(map[string]string),
+		Section: make(map[string]string),
+	}
+	for key, value := range raw {
+		if rule, ok := value.(string); ok {
+			if _, exists := p.Page[key]; !exists {
+				p.Page[key] = rule
+			}
+		}
+		rules, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		var target map[string]string
+		switch key {
+		case "page":
+			target = p.Page
+		case "section":
+			target = p.Section
+		default:
+			continue
+		}
+		for section, rule := range rules {
+			if s, ok := rule.(string); ok {
+				target[section] = s
+			}
+		}
+	}
+	return p
+}
+
+// URL returns the site relative URL Hugo generates for page. A url in
+// the front matter overrides any rule.
+func (p Permalinks) URL(page *Page) string {
+	if u, ok := page.Meta["url"].(string); ok && u != "" {
+		return "/" + strings.TrimLeft(u, "/")
+	}
+
+	section := page.section()
+	switch page.Kind {
+	case KindHome:
+		return "/"
+	case KindSection:
+		if rule, ok := p.Section[section]; ok {
+			return expandPermalink(rule, page)
+		}
+		return "/" + section + "/"
+	}
+
+	if rule, ok := p.Page[section]; ok {
+		return expandPermalink(rule, page)
+	}
+	if section == "" {
+		return expandPermalink("/:slugorfilename/", page)
+	}
+	return expandPermalink("/:section/:slugorfilename/", page)
+}
+
+var permalinkToken = regexp.MustCompile(`:[a-z]+`)
+
+// URL returns the site relative URL of the page, using Hugo's default
+// permalinks when none were applied.
+func (p *Page) URL() string {
+	if p.RelPermalink != "" {
+		return p.RelPermalink
+	}
+	return Permalinks{}.URL(p)
+}
+
+// expandPermalink replaces the Hugo permalink tokens in rule. Unknown
+// tokens are left as is. The result always ends in a slash, matching
+// Hugo's pretty URLs.
+func expandPermalink(rule string, page *Page) string {
+	fm := page.Front()
+	filename := page.filename()
+
+	out := permalinkToken.ReplaceAllStringFunc(rule, func(token string) string {
+		switch token {
+		case ":year":
+			return fmt.Sprintf("%04d", fm.Date.Year())
+		case ":month":
+			return fmt.Sprintf("%02d", int(fm.Date.Month()))
+		case ":monthname":
+			return strings.ToLower(fm.Date.Month().String())
+		case ":day":
+			return fmt.Sprintf("%02d", fm.Date.Day())
+		case ":weekday":
+			return fmt.Sprint(int(fm.Date.Weekday()))
+		case ":section":
+			return page.section()
+		case ":sections":
+			return path.Dir(pageKey(page.Path))
+		case ":title":
+			return urlize(page.Title())
+		case ":slug":
+			if fm.Slug != "" {
+				return fm.Slug
+			}
+			return urlize(page.Title())
+		case ":slugorfilename":
+			if fm.Slug != "" {
+				return fm.Slug
+			}
+			return filename
+		case ":filename", ":contentbasename":
+			return filename
+		}
+		return token
+	})
+
+	out = "/" + strings.Trim(path.Clean("/"+out), "/")
+	if out != "/" {
+		out += "/"
+	}
+	return out
+}
+
+// section returns the page section, deriving it from the path for
+// pages that were not loaded through a ContentIndex.
+func (p *Page) section() string {
+	if p.Section == "" && p.Kind == "" {
+		if dir, _, ok := strings.Cut(p.Path, "/"); ok {
+			return dir
+		}
+	}
+	return p.Section
+}
+
+// filename returns the content file name without its extension, or the
+// bundle directory name for leaf bundles.
+func (p *Page) filename() string {
+	return path.Base(pageKey(p.Path))
+}
+
+// urlize lowercases s and replaces runs of anything other than letters
+// and digits with a single hyphen, like Hugo's urlize.
+func urlize(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			b.WriteRune(r)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,196 @@
+package portfolio
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrPreviewInvalid = errors.New("invalid preview token")
+	ErrPreviewExpired = errors.New("preview token expired")
+	ErrPreviewRevoked = errors.New("preview token revoked")
+)
+
+const (
+	// DefaultPreviewTTL is how long a preview link stays valid by default.
+	DefaultPreviewTTL = 7 * 24 * time.Hour
+	// MaxPreviewTTL caps the lifetime of a preview link.
+	MaxPreviewTTL = 30 * 24 * time.Hour
+)
+
+// PreviewToken is the signed payload of a preview link.
+type PreviewToken struct {
+	ID      string    `json:"id"`
+	Path    string    `json:"path"`
+	Expires time.Time `json:"exp"`
+}
+
+// PreviewSigner mints and verifies HMAC signed preview tokens that grant
+// access to a single unpublished page.
+type PreviewSigner struct {
+	key []byte
+	now func() time.Time
+	// file keeps the revocations across restarts. See Persist.
+	file string
+
+	mu      sync.Mutex
+	revoked map[string]time.Time
+}
+
+// NewPreviewSigner creates a signer using key. When key is empty a
+// random key is generated, so links do not survive a restart.
+func NewPreviewSigner(key []byte) (*PreviewSigner, error) {
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate preview key: %w", err)
+		}
+	}
+	return &PreviewSigner{
+		key:     key,
+		now:     time.Now,
+		revoked: make(map[string]time.Time),
+	}, nil
+}
+
+// Persist keeps revocations in file, so a revoked link stays revoked
+// after a restart with the same key. Revocations already in the file are
+// loaded; a missing file starts empty.
+func (s *PreviewSigner) Persist(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read preview revocations: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(data) > 0 {
+		var revoked map[string]time.Time
+		if err := json.Unmarshal(data, &revoked); err != nil {
+			return fmt.Errorf("failed to parse preview revocations %s: %w", file, err)
+		}
+		maps.Copy(s.revoked, revoked)
+	}
+	s.file = file
+	return nil
+}
+
+// Mint returns a signed token for the content path valid for ttl.
+func (s *PreviewSigner) Mint(path string, ttl time.Duration) (string, PreviewToken, error) {
+	if ttl <= 0 {
+		ttl = DefaultPreviewTTL
+	}
+	ttl = min(ttl, MaxPreviewTTL)
+
+	id := make([]byte, 12)
+	if _, err := rand.Read(id); err != nil {
+		return "", PreviewToken{}, err
+	}
+	token := PreviewToken{
+		ID:      hex.EncodeToString(id),
+		Path:    path,
+		Expires: s.now().Add(ttl).UTC().Truncate(time.Second),
+	}
+
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return "", PreviewToken{}, err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + s.sign(encoded), token, nil
+}
+
+// Verify checks the signature, expiry and revocation state of a token
+// and returns its payload.
+func (s *PreviewSigner) Verify(raw string) (PreviewToken, error) {
+	encoded, sig, ok := strings.Cut(raw, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(s.sign(encoded))) {
+		return PreviewToken{}, ErrPreviewInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return PreviewToken{}, ErrPreviewInvalid
+	}
+	var token PreviewToken
+	if err := json.Unmarshal(payload, &token); err != nil {
+		return PreviewToken{}, ErrPreviewInvalid
+	}
+
+	if !s.now().Before(token.Expires) {
+		return token, ErrPreviewExpired
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, revoked := s.revoked[token.ID]; revoked {
+		return token, ErrPreviewRevoked
+	}
+	return token, nil
+}
+
+// Revoke invalidates the token with the given ID. Revocations are
+// forgotten once every token with that ID must have expired. The error
+// reports a failure to persist the revocation, which still applies
+// until a restart.
+func (s *PreviewSigner) Revoke(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for revokedID, exp := range s.revoked {
+		if !now.Before(exp) {
+			delete(s.revoked, revokedID)
+		}
+	}
+	s.revoked[id] = now.Add(MaxPreviewTTL)
+
+	if s.file == "" {
+		return nil
+	}
+	return s.save()
+}
+
+// save writes the revocations to s.file, replacing it atomically so a
+// crash cannot leave it truncated.
+func (s *PreviewSigner) save() error {
+	data, err := json.Marshal(s.revoked)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.file), filepath.Base(s.file)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save preview revocations: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save preview revocations: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save preview revocations: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.file); err != nil {
+		return fmt.Errorf("failed to save preview revocations: %w", err)
+	}
+	return nil
+}
+
+func (s *PreviewSigner) sign(encoded string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,161 @@
+package portfolio
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewSignerRoundTrip(t *testing.T) {
+	signer, err := NewPreviewSigner([]byte("secret"))
+	require.NoError(t, err)
+
+	raw, minted, err := signer.Mint("blog/coffee-cake.md", time.Hour)
+	require.NoError(t, err)
+
+	token, err := signer.Verify(raw)
+	require.NoError(t, err)
+	assert.Equal(t, minted, token)
+	assert.Equal(t, "blog/coffee-cake.md", token.Path)
+}
+
+func TestPreviewSignerRejects(t *testing.T) {
+	signer, err := NewPreviewSigner([]byte("secret"))
+	require.NoError(t, err)
+	other, err := NewPreviewSigner([]byte("other"))
+	require.NoError(t, err)
+
+	raw, token, err := signer.Mint("blog/coffee-cake.md", time.Hour)
+	require.NoError(t, err)
+
+	_, err = other.Verify(raw)
+	assert.ErrorIs(t, err, ErrPreviewInvalid)
+
+	_, err = signer.Verify("garbage")
+	assert.ErrorIs(t, err, ErrPreviewInvalid)
+
+	encoded, sig, _ := strings.Cut(raw, ".")
+	_, err = signer.Verify(encoded + "x." + sig)
+	assert.ErrorIs(t, err, ErrPreviewInvalid)
+
+	signer.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, err = signer.Verify(raw)
+	assert.ErrorIs(t, err, ErrPreviewExpired)
+
+	signer.now = time.Now
+	require.NoError(t, signer.Revoke(token.ID))
+	_, err = signer.Verify(raw)
+	assert.ErrorIs(t, err, ErrPreviewRevoked)
+}
+
+func TestPreviewSignerPersistsRevocations(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "revoked.json")
+
+	signer, err := NewPreviewSigner([]byte("secret"))
+	require.NoError(t, err)
+	require.NoError(t, signer.Persist(file))
+	raw, token, err := signer.Mint("blog/coffee-cake.md", time.Hour)
+	require.NoError(t, err)
+	kept, _, err := signer.Mint("blog/coffee-cake.md", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, signer.Revoke(token.ID))
+
+	// A new signer with the same key, as after a restart.
+	restarted, err := NewPreviewSigner([]byte("secret"))
+	require.NoError(t, err)
+	require.NoError(t, restarted.Persist(file))
+	_, err = restarted.Verify(raw)
+	assert.ErrorIs(t, err, ErrPreviewRevoked)
+	_, err = restarted.Verify(kept)
+	assert.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(file, []byte("{"), 0o600))
+	assert.ErrorContains(t, restarted.Persist(file), "failed to parse preview revocations")
+}
+
+func TestPreviewSignerCapsTTL(t *testing.T) {
+	signer, err := NewPreviewSigner(nil)
+	require.NoError(t, err)
+
+	_, token, err := signer.Mint("blog/coffee-cake.md", 365*24*time.Hour)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(MaxPreviewTTL), token.Expires, time.Minute)
+}
+
+func TestPreviewEndpoints(t *testing.T) {
+	config := DefaultServerConfig()
+	config.AdminToken = "admin"
+	server := NewServer(config, nil)
+
+	// Drafts are hidden without a token.
+	req := httptest.NewRequest("GET", "/posts/coffee-cake", nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Admin routes require the bearer token.
+	form := url.Values{"path": {"blog/coffee-cake"}, "ttl": {"1h"}}
+	req = httptest.NewRequest("POST", "/api/admin/previews", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req = httptest.NewRequest("POST", "/api/admin/previews", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer admin")
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+	previewURL, err := url.Parse(created.URL)
+	require.NoError(t, err)
+	raw := strings.TrimPrefix(previewURL.Path, "/preview/")
+
+	req = httptest.NewRequest("GET", previewURL.Path, nil)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `<div class="preview-banner" role="status">`)
+	assert.Contains(t, w.Body.String(), "<h1>Coffee Cake</h1>")
+	// The page is rendered with the theme, as it will be published.
+	assert.Contains(t, w.Body.String(), `<link rel="stylesheet" href="/static/css/stylesheet.css">`)
+	assert.Equal(t, "noindex, nofollow", w.Header().Get("X-Robots-Tag"))
+
+	req = httptest.NewRequest("GET", "/posts/coffee-cake?preview="+raw, nil)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "noindex, nofollow", w.Header().Get("X-Robots-Tag"))
+
+	// The token only unlocks the page it was minted for.
+	req = httptest.NewRequest("GET", "/posts/markdown-test-page?preview="+raw, nil)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	assert.Empty(t, w.Header().Get("X-Robots-Tag"))
+
+	req = httptest.NewRequest("DELETE", "/api/admin/previews/"+created.ID, nil)
+	req.Header.Set("Authorization", "Bearer admin")
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req = httptest.NewRequest("GET", previewURL.Path, nil)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,199 @@
+package portfolio
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// PublishState describes whether a page should be served, following
+// Hugo's draft, publishDate and expiryDate rules.
+type PublishState string
+
+const (
+	StatePublished PublishState = "published"
+	StateDraft     PublishState = "draft"
+	StateFuture    PublishState = "future"
+	StateExpired   PublishState = "expired"
+)
+
+// PublishOptions mirrors Hugo's --buildDrafts, --buildFuture and
+// --buildExpired flags.
+type PublishOptions struct {
+	BuildDrafts  bool
+	BuildFuture  bool
+	BuildExpired bool
+}
+
+// PreviewOptions shows every page regardless of publication state.
+var PreviewOptions = PublishOptions{
+	BuildDrafts:  true,
+	BuildFuture:  true,
+	BuildExpired: true,
+}
+
+// PublishDate returns when the page becomes public. As in Hugo it falls
+// back to the page date.
+func (p *Page) PublishDate() (time.Time, bool) {
+	if t, ok := p.metaTime("publishDate"); ok {
+		return t, true
+	}
+	if t, ok := p.metaTime("publishdate"); ok {
+		return t, true
+	}
+	date := p.Front().Date
+	return date, !date.IsZero()
+}
+
+// ExpiryDate returns when the page stops being public.
+func (p *Page) ExpiryDate() (time.Time, bool) {
+	if t, ok := p.metaTime("expiryDate"); ok {
+		return t, true
+	}
+	return p.metaTime("expirydate")
+}
+
+// State returns the publication state of the page at now.
+func (p *Page) State(now time.Time) PublishState {
+	if p.Draft() {
+		return StateDraft
+	}
+	if publish, ok := p.PublishDate(); ok && publish.After(now) {
+		return StateFuture
+	}
+	if expiry, ok := p.ExpiryDate(); ok && !expiry.After(now) {
+		return StateExpired
+	}
+	return StatePublished
+}
+
+// Visible reports whether the page should be served at now.
+func (o PublishOptions) Visible(p *Page, now time.Time) bool {
+	switch p.State(now) {
+	case StateDraft:
+		return o.BuildDrafts
+	case StateFuture:
+		return o.BuildFuture
+	case StateExpired:
+		return o.BuildExpired
+	}
+	return true
+}
+
+// Filter returns the pages visible at now.
+func (o PublishOptions) Filter(pages []*Page, now time.Time) []*Page {
+	visible := make([]*Page, 0, len(pages))
+	for _, page := range pages {
+		if o.Visible(page, now) {
+			visible = append(visible, page)
+		}
+	}
+	return visible
+}
+
+// NextTransition returns the earliest time after now at which a
+// non-draft page becomes public or expires.
+func NextTransition(pages []*Page, now time.Time) (time.Time, bool) {
+	var next time.Time
+	consider := func(t time.Time, ok bool) {
+		if ok && t.After(now) && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	for _, page := range pages {
+		if page.Draft() {
+			continue
+		}
+		consider(page.PublishDate())
+		consider(page.ExpiryDate())
+	}
+	return next, !next.IsZero()
+}
+
+// metaTime parses a front matter date value.
+func (p *Page) metaTime(key string) (time.Time, bool) {
+	t, err := asTime(p.Meta[key])
+	return t, err == nil && !t.IsZero()
+}
+
+// Scheduler fires a callback whenever a scheduled page is published or
+// expires, so cached listings pick up the change without a restart.
+type Scheduler struct {
+	pages  func(ctx context.Context) ([]*Page, error)
+	now    func() time.Time
+	logger *slog.Logger
+
+	mu        sync.Mutex
+	listeners []func()
+	timer     *time.Timer
+	stopped   bool
+}
+
+// NewScheduler creates a Scheduler over the pages returned by pages.
+func NewScheduler(
+	pages func(ctx context.Context) ([]*Page, error),
+	logger *slog.Logger,
+) *Scheduler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Scheduler{pages: pages, now: time.Now, logger: logger}
+}
+
+// OnChange registers fn to run at each publication transition.
+func (s *Scheduler) OnChange(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// Start arms the timer for the next transition.
+func (s *Scheduler) Start(ctx context.Context) error {
+	pages, err := s.pages(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return nil
+	}
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+
+	next, ok := NextTransition(pages, s.now())
+	if !ok {
+		return nil
+	}
+	s.logger.Debug("scheduled next publication transition", "at", next)
+	s.timer = time.AfterFunc(next.Sub(s.now()), s.fire)
+	return nil
+}
+
+// Stop cancels any pending transition.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+}
+
+func (s *Scheduler) fire() {
+	s.mu.Lock()
+	listeners := append([]func(){}, s.listeners...)
+	s.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn()
+	}
+	if err := s.Start(context.Background()); err != nil {
+		s.logger.Error("failed to reschedule publication", "error", err)
+	}
+}
@@ -0,0 +1,101 @@
+package portfolio
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageState(t *testing.T) {
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		meta     map[string]any
+		expected PublishState
+	}{
+		{name: "no dates", meta: map[string]any{}, expected: StatePublished},
+		{name: "past date", meta: map[string]any{"date": "2025-01-06"}, expected: StatePublished},
+		{name: "draft", meta: map[string]any{"date": "2025-01-06", "draft": true}, expected: StateDraft},
+		{name: "future date", meta: map[string]any{"date": "2025-06-02"}, expected: StateFuture},
+		{name: "rfc3339 future", meta: map[string]any{"date": "2025-06-01T13:00:00Z"}, expected: StateFuture},
+		{
+			name:     "publishDate overrides date",
+			meta:     map[string]any{"date": "2025-01-06", "publishDate": "2025-07-01"},
+			expected: StateFuture,
+		},
+		{
+			name:     "publishDate in the past",
+			meta:     map[string]any{"date": "2025-07-01", "publishDate": "2025-05-01"},
+			expected: StatePublished,
+		},
+		{name: "expired", meta: map[string]any{"expiryDate": "2025-05-31"}, expected: StateExpired},
+		{name: "not yet expired", meta: map[string]any{"expiryDate": "2025-06-02"}, expected: StatePublished},
+		{name: "time value", meta: map[string]any{"date": now.Add(time.Hour)}, expected: StateFuture},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := &Page{Meta: tt.meta}
+			assert.Equal(t, tt.expected, page.State(now))
+		})
+	}
+}
+
+func TestPublishOptionsVisible(t *testing.T) {
+	now := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.Local)
+	draft := &Page{Meta: map[string]any{"draft": true}}
+	future := &Page{Meta: map[string]any{"date": "2026-01-01"}}
+	expired := &Page{Meta: map[string]any{"expiryDate": "2025-01-01"}}
+	public := &Page{Meta: map[string]any{"date": "2025-01-01"}}
+	pages := []*Page{draft, future, expired, public}
+
+	assert.Equal(t, []*Page{public}, PublishOptions{}.Filter(pages, now))
+	assert.Equal(t, pages, PreviewOptions.Filter(pages, now))
+	assert.Equal(t,
+		[]*Page{future, public},
+		PublishOptions{BuildFuture: true}.Filter(pages, now),
+	)
+}
+
+func TestNextTransition(t *testing.T) {
+	now := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.Local)
+	pages := []*Page{
+		{Meta: map[string]any{"date": "2025-08-01"}},
+		{Meta: map[string]any{"date": "2025-07-01", "draft": true}},
+		{Meta: map[string]any{"date": "2025-01-01", "expiryDate": "2025-07-15"}},
+	}
+
+	next, ok := NextTransition(pages, now)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, time.July, 15, 0, 0, 0, 0, time.Local), next)
+
+	_, ok = NextTransition(pages[:2], time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local))
+	assert.False(t, ok)
+}
+
+func TestSchedulerFires(t *testing.T) {
+	publishAt := time.Now().Add(50 * time.Millisecond)
+	pages := []*Page{
+		{Meta: map[string]any{"date": publishAt.Format(time.RFC3339Nano)}},
+	}
+
+	scheduler := NewScheduler(func(context.Context) ([]*Page, error) {
+		return pages, nil
+	}, nil)
+	defer scheduler.Stop()
+
+	fired := make(chan struct{}, 1)
+	scheduler.OnChange(func() { fired <- struct{}{} })
+	require.NoError(t, scheduler.Start(context.Background()))
+
+	select {
+	case <-fired:
+		assert.Equal(t, StatePublished, pages[0].State(time.Now()))
+	case <-time.After(2 * time.Second):
+		t.Fatal("scheduler did not fire")
+	}
+}
@@ -0,0 +1,298 @@
+package portfolio
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// RelatedWeights weighs the signals used to score related pages.
+type RelatedWeights struct {
+	// Tags scores the Jaccard overlap of normalized tags.
+	Tags float64
+	// Text scores the TF-IDF cosine similarity of the rendered text.
+	Text float64
+	// Recency scores how close two pages are in publication date.
+	Recency float64
+}
+
+// DefaultRelatedWeights favours shared tags, then shared vocabulary.
+var DefaultRelatedWeights = RelatedWeights{Tags: 0.5, Text: 0.4, Recency: 0.1}
+
+// RelatedOptions configures the related pages engine. Zero values use
+// the defaults.
+type RelatedOptions struct {
+	Weights RelatedWeights
+	// Limit is how many related pages are precomputed for each page.
+	Limit int
+	// HalfLife is the date distance at which the recency score halves.
+	HalfLife time.Duration
+	// AllSections relates pages across sections. By default a page is
+	// only related to pages of its own section, so a post is never
+	// related to the about page.
+	AllSections bool
+}
+
+const (
+	defaultRelatedLimit    = 10
+	defaultRelatedHalfLife = 365 * 24 * time.Hour
+)
+
+// Related is a page related to another with its score in [0, 1].
+type Related struct {
+	Page  *Page
+	Score float64
+}
+
+// RelatedIndex holds the precomputed related pages of every page.
+type RelatedIndex struct {
+	// related holds every match, best first, so pages hidden at lookup
+	// time can be skipped without running short of matches.
+	related map[*Page][]Related
+	limit   int
+}
+
+// NewRelatedIndex scores every pair of pages and sorts the matches of
+// each, so lookups do no work. Tags are folded through aliases, such as
+// the site's TermAliases.
+func NewRelatedIndex(pages []*Page, aliases map[string]string, opts RelatedOptions) *RelatedIndex {
+	if opts.Weights == (RelatedWeights{}) {
+		opts.Weights = DefaultRelatedWeights
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = defaultRelatedLimit
+	}
+	if opts.HalfLife <= 0 {
+		opts.HalfLife = defaultRelatedHalfLife
+	}
+	total := opts.Weights.Tags + opts.Weights.Text + opts.Weights.Recency
+
+	tags := make([]map[string]bool, len(pages))
+	for i, page := range pages {
+		tags[i] = make(map[string]bool)
+		for _, tag := range page.Tags() {
+			if key := NormalizeTerm(tag, aliases); key != "" {
+				tags[i][key] = true
+			}
+		}
+	}
+	vectors := tfidfVectors(pages)
+
+	idx := &RelatedIndex{related: make(map[*Page][]Related, len(pages)), limit: opts.Limit}
+	for i, a := range pages {
+		var scored []Related
+		for j, b := range pages {
+			if i == j || (!opts.AllSections && a.section() != b.section()) {
+				continue
+			}
+			tagScore := jaccard(tags[i], tags[j])
+			textScore := cosine(vectors[i], vectors[j])
+			if tagScore == 0 && textScore == 0 {
+				continue
+			}
+			distance := math.Abs(a.Front().Date.Sub(b.Front().Date).Hours())
+			recency := math.Exp2(-distance / opts.HalfLife.Hours())
+
+			score := opts.Weights.Tags*tagScore +
+				opts.Weights.Text*textScore +
+				opts.Weights.Recency*recency
+			if total > 0 {
+				score /= total
+			}
+			scored = append(scored, Related{Page: b, Score: score})
+		}
+
+		sort.SliceStable(scored, func(x, y int) bool {
+			if scored[x].Score != scored[y].Score {
+				return scored[x].Score > scored[y].Score
+			}
+			return scored[x].Page.Path < scored[y].Page.Path
+		})
+		idx.related[a] = scored
+	}
+	return idx
+}
+
+// For returns up to the limit of related pages of page, best first.
+func (r *RelatedIndex) For(page *Page) []Related {
+	return r.Filter(page, nil)
+}
+
+// Filter is For counting only the related pages keep accepts, such as
+// those visible at the time of the request. A nil keep accepts all.
+func (r *RelatedIndex) Filter(page *Page, keep func(*Page) bool) []Related {
+	if r == nil {
+		return nil
+	}
+	var related []Related
+	for _, match := range r.related[page] {
+		if len(related) == r.limit {
+			break
+		}
+		if keep == nil || keep(match.Page) {
+			related = append(related, match)
+		}
+	}
+	return related
+}
+
+// RelatedOptions reads the related site param:
+//
+//	related:
+//	  limit: 10
+//	  halfLife: 8760h
+//	  weights: {tags: 0.5, text: 0.4, recency: 0.1}
+//	  allSections: false
+func (c *SiteConfig) RelatedOptions() RelatedOptions {
+	var opts RelatedOptions
+	raw, _ := c.Params["related"].(map[string]any)
+	if limit, ok := raw["limit"].(int); ok {
+		opts.Limit = limit
+	}
+	opts.AllSections, _ = raw["allSections"].(bool)
+	if s, ok := raw["halfLife"].(string); ok {
+		opts.HalfLife, _ = time.ParseDuration(s)
+	}
+	weights, _ := raw["weights"].(map[string]any)
+	opts.Weights.Tags = asFloat(weights["tags"])
+	opts.Weights.Text = asFloat(weights["text"])
+	opts.Weights.Recency = asFloat(weights["recency"])
+	return opts
+}
+
+func asFloat(value any) float64 {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// BuildRelated precomputes related pages across the regular pages of
+// the index.
+func (idx *ContentIndex) BuildRelated(aliases map[string]string, opts RelatedOptions) {
+	var pages []*Page
+	for _, page := range idx.pages {
+		if page.Kind == KindPage {
+			pages = append(pages, page)
+		}
+	}
+	idx.related = NewRelatedIndex(pages, aliases, opts)
+}
+
+// Related returns the related pages of page, best first.
+func (idx *ContentIndex) Related(page *Page) []Related {
+	return idx.related.For(page)
+}
+
+// RelatedPages returns up to n visible pages related to the page ref
+// resolves to.
+func (m *AssetManager) RelatedPages(ctx context.Context, ref string, n int) ([]Related, error) {
+	page, err := m.GetPage(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := m.Index(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := m.now()
+	related := idx.related.Filter(page, func(p *Page) bool {
+		return m.Publish.Visible(p, now)
+	})
+	if n < len(related) {
+		related = related[:n]
+	}
+	return related, nil
+}
+
+// tfidfVectors returns a unit length TF-IDF vector of the rendered text
+// of each page.
+func tfidfVectors(pages []*Page) []map[string]float64 {
+	counts := make([]map[string]float64, len(pages))
+	docFreq := make(map[string]int)
+	for i, page := range pages {
+		counts[i] = make(map[string]float64)
+		for _, word := range tokenize(plainText(page.Content)) {
+			counts[i][word]++
+		}
+		for word := range counts[i] {
+			docFreq[word]++
+		}
+	}
+
+	n := float64(len(pages))
+	for _, tf := range counts {
+		var norm float64
+		for word, count := range tf {
+			weight := count * math.Log(1+n/float64(docFreq[word]))
+			tf[word] = weight
+			norm += weight * weight
+		}
+		norm = math.Sqrt(norm)
+		if norm == 0 {
+			continue
+		}
+		for word := range tf {
+			tf[word] /= norm
+		}
+	}
+	return counts
+}
+
+func cosine(a, b map[string]float64) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	var dot float64
+	for word, weight := range a {
+		dot += weight * b[word]
+	}
+	return dot
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for key := range a {
+		if b[key] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// stopWords are common English words that carry no topic.
+var stopWords = map[string]bool{}
+
+func init() {
+	for word := range strings.FieldsSeq(`a about an and are as at be but by can do
+		for from has have how i if in into is it its just more not of on or our
+		so that the their then there these they this to was we what when which
+		will with you your`) {
+		stopWords[word] = true
+	}
+}
+
+// tokenize splits text into lowercase words, dropping stop words and
+// single characters.
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := words[:0]
+	for _, word := range words {
+		if len(word) > 1 && !stopWords[word] {
+			out = append(out, word)
+		}
+	}
+	return out
+}
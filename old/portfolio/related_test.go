@@ -0,0 +1,159 @@
+package portfolio
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func relatedFixture() []*Page {
+	return []*Page{
+		testPage("blog/keg.md", "---\ntitle: Keg\ndate: 2025-01-01\ntags: [go, notes]\n---\nA keg stores zettel notes as markdown nodes.\n"),
+		testPage("blog/zettel.md", "---\ntitle: Zettel\ndate: 2025-02-01\ntags: [notes]\n---\nZettel notes link nodes into a keg of markdown.\n"),
+		testPage("blog/dispatch.md", "---\ntitle: Dispatch\ndate: 2025-03-01\ntags: [golang]\n---\nType erasure dispatch with interfaces and generics.\n"),
+		testPage("blog/cake.md", "---\ntitle: Cake\ndate: 2020-01-01\n---\nCoffee cake with cinnamon streusel.\n"),
+	}
+}
+
+func TestNewRelatedIndex(t *testing.T) {
+	pages := relatedFixture()
+	idx := NewRelatedIndex(pages, DefaultTermAliases, RelatedOptions{})
+
+	related := idx.For(pages[0])
+	require.Len(t, related, 2)
+	assert.Equal(t, "Zettel", related[0].Page.Title())
+	assert.Equal(t, "Dispatch", related[1].Page.Title())
+	assert.Greater(t, related[0].Score, related[1].Score)
+	for _, r := range related {
+		assert.LessOrEqual(t, r.Score, 1.0)
+	}
+
+	// Unrelated pages are never recommended.
+	assert.Empty(t, idx.For(pages[3]))
+	assert.Nil(t, idx.For(testPage("blog/other.md", "---\ntitle: Other\n---\n")))
+}
+
+func TestRelatedWeights(t *testing.T) {
+	pages := relatedFixture()
+
+	// With only tags counted, "golang" matches the "go" tag through the
+	// default aliases.
+	tagsOnly := NewRelatedIndex(pages, DefaultTermAliases, RelatedOptions{Weights: RelatedWeights{Tags: 1}})
+	related := tagsOnly.For(pages[2])
+	require.NotEmpty(t, related)
+	assert.Equal(t, "Keg", related[0].Page.Title())
+	assert.InDelta(t, 1.0/2.0, related[0].Score, 1e-9)
+
+	limited := NewRelatedIndex(pages, DefaultTermAliases, RelatedOptions{Limit: 1})
+	assert.Len(t, limited.For(pages[0]), 1)
+}
+
+func TestRelatedSections(t *testing.T) {
+	pages := append(relatedFixture(),
+		testPage("notes.md", "---\ntitle: Notes\ntags: [notes]\n---\nZettel notes in a keg of markdown nodes.\n"))
+
+	related := NewRelatedIndex(pages, DefaultTermAliases, RelatedOptions{}).For(pages[0])
+	assert.NotContains(t, relatedTitles(related), "Notes")
+
+	related = NewRelatedIndex(pages, DefaultTermAliases, RelatedOptions{AllSections: true}).For(pages[0])
+	assert.Contains(t, relatedTitles(related), "Notes")
+}
+
+func TestRelatedPagesSkipsHidden(t *testing.T) {
+	manager := &AssetManager{
+		Assets: fstest.MapFS{
+			"hugo.yaml":             {Data: []byte("title: Test\n")},
+			"content/blog/keg.md":   {Data: []byte("---\ntitle: Keg\ntags: [notes]\n---\nA keg stores zettel notes.\n")},
+			"content/blog/draft.md": {Data: []byte("---\ntitle: Draft\ntags: [notes]\ndraft: true\n---\nA keg stores zettel notes.\n")},
+			"content/blog/other.md": {Data: []byte("---\ntitle: Other\ntags: [notes]\n---\nZettel notes.\n")},
+		},
+		Now:     time.Now,
+		Related: RelatedOptions{Limit: 1},
+	}
+
+	// The draft is the best match, but is hidden, so the next match
+	// fills the limit.
+	related, err := manager.RelatedPages(context.Background(), "blog/keg", 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Other"}, relatedTitles(related))
+}
+
+func TestRelatedPagesSiteAliases(t *testing.T) {
+	manager := &AssetManager{
+		Assets: fstest.MapFS{
+			"hugo.yaml":              {Data: []byte("title: Test\nparams:\n  termAliases:\n    k8s: kubernetes\n")},
+			"content/blog/pods.md":   {Data: []byte("---\ntitle: Pods\ntags: [k8s]\n---\nScheduling pods.\n")},
+			"content/blog/helm.md":   {Data: []byte("---\ntitle: Helm\ntags: [kubernetes]\n---\nCharts for releases.\n")},
+			"content/blog/coffee.md": {Data: []byte("---\ntitle: Coffee\ntags: [cake]\n---\nCinnamon streusel.\n")},
+		},
+		Now:     time.Now,
+		Related: RelatedOptions{Weights: RelatedWeights{Tags: 1}},
+	}
+
+	related, err := manager.RelatedPages(context.Background(), "blog/pods", 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Helm"}, relatedTitles(related))
+}
+
+func relatedTitles(related []Related) []string {
+	titles := make([]string, 0, len(related))
+	for _, r := range related {
+		titles = append(titles, r.Page.Title())
+	}
+	return titles
+}
+
+func TestSiteConfigRelatedOptions(t *testing.T) {
+	site, err := NewSiteConfig(map[string]any{
+		"params": map[string]any{
+			"related": map[string]any{
+				"limit":    3,
+				"halfLife": "720h",
+				"weights":  map[string]any{"tags": 1, "text": 0.5},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, RelatedOptions{
+		Weights:  RelatedWeights{Tags: 1, Text: 0.5},
+		Limit:    3,
+		HalfLife: 720 * time.Hour,
+	}, site.RelatedOptions())
+}
+
+func TestHandleRelated(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), nil)
+
+	req := httptest.NewRequest("GET", "/api/related/what-is-a-keg?limit=2", nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Related []struct {
+			Slug  string  `json:"slug"`
+			Score float64 `json:"score"`
+		} `json:"related"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.LessOrEqual(t, len(body.Related), 2)
+	for _, r := range body.Related {
+		assert.NotEqual(t, "what-is-a-keg", r.Slug)
+		assert.Greater(t, r.Score, 0.0)
+	}
+
+	for _, path := range []string{"/api/related/what-is-a-keg?limit=0", "/api/related/nope"} {
+		req = httptest.NewRequest("GET", path, nil)
+		w = httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		assert.NotEqual(t, http.StatusOK, w.Code, path)
+	}
+}
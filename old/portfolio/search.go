@@ -0,0 +1,525 @@
+package portfolio
+
+import (
+	"context"
+	"hash/fnv"
+	"html"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SearchBoosts weighs where a term appears in a document.
+type SearchBoosts struct {
+	Title       float64
+	Tags        float64
+	Description float64
+	Body        float64
+}
+
+// DefaultSearchBoosts ranks title matches above tags, description and
+// body text.
+var DefaultSearchBoosts = SearchBoosts{Title: 3, Tags: 2, Description: 1.5, Body: 1}
+
+// BM25 parameters.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Search limits.
+const (
+	DefaultSearchLimit = 10
+	MaxSearchLimit     = 50
+	snippetWords       = 30
+)
+
+// SearchDocument is a searchable unit such as a post or an experience
+// entry from the résumé.
+type SearchDocument struct {
+	ID string `json:"id"`
+	// Type is one of the Search* document types.
+	Type        string    `json:"type"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Description string    `json:"description,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Date        time.Time `json:"date,omitzero"`
+	// Body is the plain text of the document.
+	Body string `json:"-"`
+}
+
+// SearchResult is a ranked search hit. Snippet is HTML with the
+// matched words wrapped in mark elements.
+type SearchResult struct {
+	SearchDocument
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet,omitempty"`
+}
+
+// SearchOptions configures a query. Zero values use the defaults.
+type SearchOptions struct {
+	Limit int
+	// Types restricts results to documents of these types.
+	Types []string
+}
+
+// SearchIndex is an in-memory inverted index ranked with BM25. It is
+// safe for concurrent use and can be updated one document at a time.
+type SearchIndex struct {
+	boosts SearchBoosts
+
+	mu       sync.RWMutex
+	docs     map[string]*searchEntry
+	postings map[string]map[string]bool
+	words    map[string]int
+	length   float64
+}
+
+type searchEntry struct {
+	doc    SearchDocument
+	hash   uint64
+	terms  map[string]float64
+	words  []string
+	length float64
+}
+
+// NewSearchIndex returns an empty index. Zero boosts use
+// DefaultSearchBoosts.
+func NewSearchIndex(boosts SearchBoosts) *SearchIndex {
+	if boosts == (SearchBoosts{}) {
+		boosts = DefaultSearchBoosts
+	}
+	return &SearchIndex{
+		boosts:   boosts,
+		docs:     make(map[string]*searchEntry),
+		postings: make(map[string]map[string]bool),
+		words:    make(map[string]int),
+	}
+}
+
+// Len returns the number of indexed documents.
+func (s *SearchIndex) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.docs)
+}
+
+// Add indexes doc, replacing any document with the same ID.
+func (s *SearchIndex) Add(doc SearchDocument) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.add(doc, documentHash(doc))
+}
+
+// Remove drops the document with id and reports whether it was indexed.
+func (s *SearchIndex) Remove(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.remove(id)
+}
+
+// Sync makes the index hold exactly docs. Only new and changed
+// documents are reindexed.
+func (s *SearchIndex) Sync(docs []SearchDocument) (added, updated, removed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keep := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		keep[doc.ID] = true
+		hash := documentHash(doc)
+		entry, ok := s.docs[doc.ID]
+		switch {
+		case !ok:
+			added++
+		case entry.hash != hash:
+			updated++
+		default:
+			continue
+		}
+		s.add(doc, hash)
+	}
+	for id := range s.docs {
+		if !keep[id] {
+			s.remove(id)
+			removed++
+		}
+	}
+	return added, updated, removed
+}
+
+func (s *SearchIndex) add(doc SearchDocument, hash uint64) {
+	s.remove(doc.ID)
+
+	entry := &searchEntry{doc: doc, hash: hash, terms: make(map[string]float64)}
+	words := make(map[string]bool)
+	field := func(text string, boost float64) {
+		for _, word := range tokenize(text) {
+			entry.terms[stem(word)] += boost
+			entry.length += boost
+			words[word] = true
+		}
+	}
+	field(doc.Title, s.boosts.Title)
+	field(strings.Join(doc.Tags, " "), s.boosts.Tags)
+	field(doc.Description, s.boosts.Description)
+	field(doc.Body, s.boosts.Body)
+
+	for term := range entry.terms {
+		if s.postings[term] == nil {
+			s.postings[term] = make(map[string]bool)
+		}
+		s.postings[term][doc.ID] = true
+	}
+	for word := range words {
+		entry.words = append(entry.words, word)
+		s.words[word]++
+	}
+	s.docs[doc.ID] = entry
+	s.length += entry.length
+}
+
+func (s *SearchIndex) remove(id string) bool {
+	entry, ok := s.docs[id]
+	if !ok {
+		return false
+	}
+	for term := range entry.terms {
+		delete(s.postings[term], id)
+		if len(s.postings[term]) == 0 {
+			delete(s.postings, term)
+		}
+	}
+	for _, word := range entry.words {
+		if s.words[word]--; s.words[word] <= 0 {
+			delete(s.words, word)
+		}
+	}
+	s.length -= entry.length
+	delete(s.docs, id)
+	return true
+}
+
+// Search ranks the documents matching query. Each query word also
+// matches terms within a small edit distance, and the last word
+// matches as a prefix so results update while typing.
+func (s *SearchIndex) Search(query string, opts SearchOptions) []SearchResult {
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultSearchLimit
+	}
+	opts.Limit = min(opts.Limit, MaxSearchLimit)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	words := tokenize(query)
+	if len(words) == 0 || len(s.docs) == 0 {
+		return []SearchResult{}
+	}
+	typing := !strings.HasSuffix(query, " ")
+
+	type hit struct {
+		entry   *searchEntry
+		score   float64
+		matched int
+		terms   map[string]bool
+	}
+	hits := make(map[string]*hit)
+	for i, word := range words {
+		expanded := s.expand(word, typing && i == len(words)-1)
+		best := make(map[string]float64)
+		for term, weight := range expanded {
+			for id := range s.postings[term] {
+				score := weight * s.bm25(term, s.docs[id])
+				h := hits[id]
+				if h == nil {
+					h = &hit{entry: s.docs[id], terms: make(map[string]bool)}
+					hits[id] = h
+				}
+				h.terms[term] = true
+				best[id] = max(best[id], score)
+			}
+		}
+		for id, score := range best {
+			hits[id].score += score
+			hits[id].matched++
+		}
+	}
+
+	ranked := make([]*hit, 0, len(hits))
+	for _, h := range hits {
+		if len(opts.Types) > 0 && !slices.Contains(opts.Types, h.entry.doc.Type) {
+			continue
+		}
+		// Documents matching every query word rank above partial matches.
+		h.score *= float64(h.matched) / float64(len(words))
+		ranked = append(ranked, h)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].entry.doc.ID < ranked[j].entry.doc.ID
+	})
+	if len(ranked) > opts.Limit {
+		ranked = ranked[:opts.Limit]
+	}
+
+	results := make([]SearchResult, 0, len(ranked))
+	for _, h := range ranked {
+		results = append(results, SearchResult{
+			SearchDocument: h.entry.doc,
+			Score:          h.score,
+			Snippet:        snippet(h.entry.doc.Body, h.terms),
+		})
+	}
+	return results
+}
+
+// expand returns the indexed terms a query word matches with a weight
+// in (0, 1]: the exact stem, terms it is a prefix of when prefix is
+// set, and terms within typoDistance edits.
+func (s *SearchIndex) expand(word string, prefix bool) map[string]float64 {
+	st := stem(word)
+	terms := make(map[string]float64)
+	if s.postings[st] != nil {
+		terms[st] = 1
+	}
+
+	maxEdits := typoDistance(st)
+	for term := range s.postings {
+		if term == st {
+			continue
+		}
+		if prefix && (strings.HasPrefix(term, word) || strings.HasPrefix(term, st)) {
+			terms[term] = max(terms[term], 0.8)
+			continue
+		}
+		if maxEdits == 0 || max(len(term)-len(st), len(st)-len(term)) > maxEdits {
+			continue
+		}
+		if d := editDistance(st, term, maxEdits); d <= maxEdits {
+			terms[term] = max(terms[term], 1/float64(1+d))
+		}
+	}
+	return terms
+}
+
+// bm25 scores term for entry. Term frequencies and lengths are boosted
+// by field, as in BM25F.
+func (s *SearchIndex) bm25(term string, entry *searchEntry) float64 {
+	n := float64(len(s.docs))
+	df := float64(len(s.postings[term]))
+	idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+	tf := entry.terms[term]
+	avg := s.length / n
+	return idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*entry.length/avg))
+}
+
+// Suggest returns up to n indexed words starting with prefix, most
+// common first, for autocomplete.
+func (s *SearchIndex) Suggest(prefix string, n int) []string {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" || n <= 0 {
+		return []string{}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	suggestions := []string{}
+	for word := range s.words {
+		if len(word) > len(prefix) && strings.HasPrefix(word, prefix) {
+			suggestions = append(suggestions, word)
+		}
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if s.words[a] != s.words[b] {
+			return s.words[a] > s.words[b]
+		}
+		return a < b
+	})
+	if len(suggestions) > n {
+		suggestions = suggestions[:n]
+	}
+	return suggestions
+}
+
+// typoDistance is how many edits a query term may be from an indexed
+// term. Short words must match exactly.
+func typoDistance(term string) int {
+	switch n := len(term); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	}
+	return 2
+}
+
+// editDistance returns the optimal string alignment distance between a
+// and b, counting an adjacent transposition as one edit. It stops early
+// and returns limit+1 once the distance exceeds limit.
+func editDistance(a, b string, limit int) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
+
+var (
+	searchWord = regexp.MustCompile(`[\p{L}\p{N}]+`)
+	spaceRun   = regexp.MustCompile(`\s+`)
+)
+
+// snippet returns an HTML excerpt of text around the first word whose
+// stem is in terms, with matched words marked.
+func snippet(text string, terms map[string]bool) string {
+	words := searchWord.FindAllStringIndex(text, -1)
+	if len(words) == 0 {
+		return ""
+	}
+	matches := func(i int) bool {
+		return terms[stem(strings.ToLower(text[words[i][0]:words[i][1]]))]
+	}
+
+	first := 0
+	for i := range words {
+		if matches(i) {
+			first = i
+			break
+		}
+	}
+	start := max(0, first-snippetWords/3)
+	end := min(len(words), start+snippetWords)
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("… ")
+	}
+	pos := words[start][0]
+	for i := start; i < end; i++ {
+		b.WriteString(html.EscapeString(spaceRun.ReplaceAllString(text[pos:words[i][0]], " ")))
+		word := html.EscapeString(text[words[i][0]:words[i][1]])
+		if matches(i) {
+			b.WriteString("<mark>" + word + "</mark>")
+		} else {
+			b.WriteString(word)
+		}
+		pos = words[i][1]
+	}
+	if end < len(words) {
+		b.WriteString(" …")
+	}
+	return b.String()
+}
+
+func documentHash(doc SearchDocument) uint64 {
+	h := fnv.New64a()
+	for _, field := range []string{
+		doc.Type, doc.Title, doc.URL, doc.Description,
+		strings.Join(doc.Tags, "\x1f"), doc.Date.String(), doc.Body,
+	} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+// searchDocuments returns the documents of the visible regular pages
+// and the résumé data. Pages are still indexed when data.yaml cannot be
+// loaded.
+func (m *AssetManager) searchDocuments(ctx context.Context, idx *ContentIndex) []SearchDocument {
+	var pages []*Page
+	for _, page := range idx.Pages() {
+		if page.Kind == KindPage {
+			pages = append(pages, page)
+		}
+	}
+	var docs []SearchDocument
+	for _, page := range m.Publish.Filter(pages, m.now()) {
+		docs = append(docs, pageSearchDocument(page))
+	}
+
+	data, err := m.GetData(ctx)
+	if err != nil {
+		if m.Logger != nil {
+			m.Logger.Warn("skipping résumé data in search index", "error", err)
+		}
+		return docs
+	}
+	return append(docs, DataSearchDocuments(data, m.now())...)
+}
+
+// Search returns the search index, syncing it with the content index
+// when content was reloaded.
+func (m *AssetManager) Search(ctx context.Context) (*SearchIndex, error) {
+	idx, err := m.Index(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.search == nil {
+		m.search = NewSearchIndex(SearchBoosts{})
+	}
+	if m.searchSource != idx {
+		m.syncSearch(ctx, idx)
+	}
+	return m.search, nil
+}
+
+// SyncSearch reindexes the documents that changed, such as pages that
+// became visible on schedule.
+func (m *AssetManager) SyncSearch(ctx context.Context) error {
+	idx, err := m.Index(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.search == nil {
+		m.search = NewSearchIndex(SearchBoosts{})
+	}
+	m.syncSearch(ctx, idx)
+	return nil
+}
+
+func (m *AssetManager) syncSearch(ctx context.Context, idx *ContentIndex) {
+	added, updated, removed := m.search.Sync(m.searchDocuments(ctx, idx))
+	m.searchSource = idx
+	if m.Logger != nil {
+		m.Logger.Info("synced search index",
+			"added", added, "updated", updated, "removed", removed)
+	}
+}
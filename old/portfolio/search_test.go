@@ -0,0 +1,241 @@
+package portfolio
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func searchFixture() []SearchDocument {
+	return []SearchDocument{
+		{
+			ID:    "keg",
+			Type:  "post",
+			Title: "What is a keg",
+			URL:   "/blog/keg/",
+			Tags:  []string{"notes", "zettelkasten"},
+			Body:  "A keg stores zettel notes as markdown nodes. Searching a keg is fast.",
+		},
+		{
+			ID:          "kubernetes",
+			Type:        "post",
+			Title:       "Deploying to Kubernetes",
+			URL:         "/blog/kubernetes/",
+			Description: "Running containers in a cluster",
+			Tags:        []string{"devops"},
+			Body:        "Deployments roll out containers. Notes on searching pod logs.",
+		},
+		{
+			ID:    "cake",
+			Type:  "page",
+			Title: "Coffee cake",
+			URL:   "/blog/cake/",
+			Body:  "Cinnamon streusel on a buttery cake.",
+		},
+	}
+}
+
+func newTestSearchIndex() *SearchIndex {
+	idx := NewSearchIndex(SearchBoosts{})
+	idx.Sync(searchFixture())
+	return idx
+}
+
+func resultIDs(results []SearchResult) []string {
+	ids := make([]string, 0, len(results))
+	for _, r := range results {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestSearchIndexRanking(t *testing.T) {
+	idx := newTestSearchIndex()
+
+	// Stemming matches "deploy" to "Deploying" and "Deployments".
+	results := idx.Search("deploy ", SearchOptions{})
+	assert.Equal(t, []string{"kubernetes"}, resultIDs(results))
+
+	// The title boost ranks the keg post above a body mention.
+	results = idx.Search("notes ", SearchOptions{})
+	require.Len(t, results, 2)
+	assert.Equal(t, "keg", results[0].ID)
+	assert.Greater(t, results[0].Score, results[1].Score)
+
+	// Documents matching every word rank first.
+	results = idx.Search("searching notes keg ", SearchOptions{})
+	assert.Equal(t, "keg", results[0].ID)
+
+	assert.Len(t, idx.Search("notes ", SearchOptions{Limit: 1}), 1)
+	assert.Equal(t, []string{"cake"}, resultIDs(idx.Search("cake", SearchOptions{Types: []string{"page"}})))
+	assert.Empty(t, idx.Search("the", SearchOptions{}))
+	assert.Empty(t, idx.Search("zebra quartz", SearchOptions{}))
+}
+
+func TestSearchIndexPrefixAndTypos(t *testing.T) {
+	idx := newTestSearchIndex()
+
+	// The last word matches as a prefix while typing.
+	assert.Equal(t, []string{"kubernetes"}, resultIDs(idx.Search("kuber", SearchOptions{})))
+	assert.Empty(t, idx.Search("kuber ", SearchOptions{}))
+
+	// Typos within the edit distance still match, transpositions count
+	// as one edit.
+	assert.Equal(t, []string{"kubernetes"}, resultIDs(idx.Search("kubernetse ", SearchOptions{})))
+	assert.Equal(t, []string{"cake"}, resultIDs(idx.Search("cinamon ", SearchOptions{})))
+	// Short words must match exactly.
+	assert.Empty(t, idx.Search("kag ", SearchOptions{}))
+
+	assert.Equal(t, []string{"kubernetes"}, idx.Suggest("Kub", 5))
+	// Words used by more documents come first.
+	assert.Equal(t, []string{"searching", "stores"}, idx.Suggest("s", 2))
+	assert.Empty(t, idx.Suggest("", 5))
+}
+
+func TestSearchIndexSnippet(t *testing.T) {
+	idx := newTestSearchIndex()
+
+	results := idx.Search("searching ", SearchOptions{})
+	require.NotEmpty(t, results)
+	assert.Contains(t, results[0].Snippet, "<mark>Searching</mark>")
+
+	long := SearchDocument{
+		ID:    "long",
+		Title: "Long",
+		Body:  strings.Repeat("filler ", 40) + "needle <b> " + strings.Repeat("filler ", 40),
+	}
+	idx.Add(long)
+	results = idx.Search("needle ", SearchOptions{})
+	require.Len(t, results, 1)
+	assert.True(t, strings.HasPrefix(results[0].Snippet, "… filler"))
+	assert.True(t, strings.HasSuffix(results[0].Snippet, "filler …"))
+	assert.Contains(t, results[0].Snippet, "<mark>needle</mark> &lt;b&gt; filler")
+}
+
+func TestSearchIndexSync(t *testing.T) {
+	idx := newTestSearchIndex()
+	require.Equal(t, 3, idx.Len())
+
+	docs := searchFixture()
+	docs[0].Title = "What is a barrel"
+	docs = docs[:2]
+	docs = append(docs, SearchDocument{ID: "new", Title: "Brand new"})
+
+	added, updated, removed := idx.Sync(docs)
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 1, updated)
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, 3, idx.Len())
+
+	assert.Equal(t, []string{"keg"}, resultIDs(idx.Search("barrel ", SearchOptions{})))
+	assert.Empty(t, idx.Search("cinnamon ", SearchOptions{}))
+	assert.Empty(t, idx.Suggest("cinn", 5), "words of removed documents are dropped")
+
+	added, updated, removed = idx.Sync(docs)
+	assert.Zero(t, added+updated+removed)
+
+	assert.True(t, idx.Remove("new"))
+	assert.False(t, idx.Remove("new"))
+}
+
+func TestHandleSearch(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), nil)
+
+	req := httptest.NewRequest("GET", "/search?q=keg", nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Total   int `json:"total"`
+		Results []struct {
+			Type    string  `json:"type"`
+			URL     string  `json:"url"`
+			Score   float64 `json:"score"`
+			Snippet string  `json:"snippet"`
+		} `json:"results"`
+		Suggestions []string `json:"suggestions"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	require.NotEmpty(t, body.Results)
+	assert.Equal(t, "post", body.Results[0].Type)
+	assert.Equal(t, "/blog/what-is-a-keg/", body.Results[0].URL)
+	assert.Contains(t, body.Results[0].Snippet, "<mark>")
+
+	for _, path := range []string{"/search", "/search?q=+", "/search?q=keg&limit=0"} {
+		req = httptest.NewRequest("GET", path, nil)
+		w = httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+}
+
+func TestHandleReload(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	write("hugo.yaml", "title: Test\n")
+	write("content/blog/first.md", "---\ntitle: First\ndate: 2025-01-01\n---\nHello\n")
+
+	config := DefaultServerConfig()
+	config.AdminToken = "admin"
+	config.SiteDir = dir
+	server := NewServer(config, nil)
+	defer server.scheduler.Stop()
+	defer server.watcher.Stop()
+
+	armed := func() bool {
+		server.scheduler.mu.Lock()
+		defer server.scheduler.mu.Unlock()
+		return server.scheduler.timer != nil
+	}
+	assert.False(t, armed())
+
+	write("content/blog/later.md", "---\ntitle: Later\ndate: "+time.Now().Add(time.Hour).Format(time.RFC3339)+"\n---\nSoon\n")
+	req := httptest.NewRequest("POST", "/api/admin/reload", nil)
+	req.Header.Set("Authorization", "Bearer admin")
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// The new post is scheduled to be published.
+	assert.True(t, armed())
+}
+
+func TestSiteDirWatch(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	write("hugo.yaml", "title: Test\n")
+	write("content/blog/first.md", "---\ntitle: First\ndate: 2025-01-01\n---\nHello\n")
+
+	config := DefaultServerConfig()
+	config.SiteDir = dir
+	config.WatchInterval = 10 * time.Millisecond
+	server := NewServer(config, nil)
+	defer server.scheduler.Stop()
+	defer server.watcher.Stop()
+
+	found := func() bool {
+		idx, err := server.assetManager.Search(context.Background())
+		return err == nil && len(idx.Search("zeppelin", SearchOptions{})) > 0
+	}
+	require.False(t, found())
+
+	// Edits are indexed without an admin reload.
+	write("content/blog/second.md", "---\ntitle: Second\ndate: 2025-01-02\n---\nA zeppelin\n")
+	assert.Eventually(t, found, 2*time.Second, 10*time.Millisecond)
+}
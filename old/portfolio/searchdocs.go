@@ -0,0 +1,122 @@
+package portfolio
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Search document types.
+const (
+	SearchPost          = "post"
+	SearchPage          = "page"
+	SearchProject       = "project"
+	SearchExperience    = "experience"
+	SearchSkill         = "skill"
+	SearchCertification = "certification"
+)
+
+// pageSearchDocument adapts a content page for the search index.
+// Project bundles include their external link so it can be found too.
+func pageSearchDocument(page *Page) SearchDocument {
+	doc := SearchDocument{
+		ID:          "page:" + page.Path,
+		Type:        SearchPage,
+		Title:       page.Title(),
+		URL:         page.URL(),
+		Description: page.Description(),
+		Tags:        page.Tags(),
+		Date:        page.Front().Date,
+		Body:        plainText(page.Content),
+	}
+	switch page.section() {
+	case "blog":
+		doc.Type = SearchPost
+	case "projects":
+		doc.Type = SearchProject
+		if link := page.Front().Link; link != "" {
+			doc.Body += " " + link
+		}
+	}
+	return doc
+}
+
+// DataSearchDocuments adapts the résumé data for the search index: each
+// experience with its highlights and technologies, each skill with
+// where it was used, and each certification. Each links to its entry
+// on the home page.
+func DataSearchDocuments(data *Data, now time.Time) []SearchDocument {
+	var docs []SearchDocument
+	for _, exp := range data.Experience {
+		anchor := exp.Anchor()
+		start, _ := exp.Start()
+		end := exp.EndDate
+		if exp.Current {
+			end = "Present"
+		}
+		docs = append(docs, SearchDocument{
+			ID:          "data:" + anchor,
+			Type:        SearchExperience,
+			Title:       fmt.Sprintf("%s at %s", exp.Title, exp.Company),
+			URL:         "/#" + anchor,
+			Description: joinNonEmpty(" · ", exp.Location, joinNonEmpty(" - ", exp.StartDate, end)),
+			Tags:        exp.TechnologyList(),
+			Date:        start,
+			Body:        strings.Join(exp.Highlights, " "),
+		})
+	}
+
+	for _, profile := range NewSkillIndex(data, now).All() {
+		anchor := profile.Anchor()
+		var companies []string
+		for _, usage := range profile.Usages {
+			if !slices.Contains(companies, usage.Company) {
+				companies = append(companies, usage.Company)
+			}
+		}
+		description := humanize(profile.Category)
+		if len(companies) > 0 {
+			description += " · used at " + strings.Join(companies, ", ")
+		}
+		docs = append(docs, SearchDocument{
+			ID:          "data:" + anchor,
+			Type:        SearchSkill,
+			Title:       profile.Name,
+			URL:         "/#" + anchor,
+			Description: description,
+			Tags:        profile.Aliases,
+			Body:        string(profile.Proficiency),
+		})
+	}
+
+	for _, cert := range data.Certifications {
+		anchor := cert.Anchor()
+		docs = append(docs, SearchDocument{
+			ID:          "data:" + anchor,
+			Type:        SearchCertification,
+			Title:       cert.Name,
+			URL:         "/#" + anchor,
+			Description: joinNonEmpty(" · ", prefixed("Issued ", cert.Issued), prefixed("Expires ", cert.Expires)),
+			Body:        cert.CredentialID,
+		})
+	}
+	return docs
+}
+
+func joinNonEmpty(sep string, values ...string) string {
+	var parts []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+func prefixed(prefix, value string) string {
+	if value == "" {
+		return ""
+	}
+	return prefix + value
+}
@@ -0,0 +1,126 @@
+package portfolio
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataSearchDocuments(t *testing.T) {
+	data, err := LoadData([]byte(skillsYAML + `
+certifications:
+  - name: AWS Certified Developer
+    issued: March 2023
+    credential_id: ABC123
+`))
+	require.NoError(t, err)
+	experience := data.Experience[0]
+	experience.Highlights = []string{"Migrated single sign-on to a self hosted identity provider"}
+	data.Experience[0] = experience
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	docs := DataSearchDocuments(data, now)
+
+	byID := make(map[string]SearchDocument)
+	for _, doc := range docs {
+		byID[doc.ID] = doc
+	}
+
+	exp := byID["data:experience-ecreative-web-developer"]
+	assert.Equal(t, SearchExperience, exp.Type)
+	assert.Equal(t, "Web Developer at Ecreative", exp.Title)
+	assert.Equal(t, "/#experience-ecreative-web-developer", exp.URL)
+	assert.Equal(t, "April 2024 - Present", exp.Description)
+	assert.Equal(t, []string{"PHP", "MySQL", "Keycloak"}, exp.Tags)
+	assert.Contains(t, exp.Body, "identity provider")
+
+	skill := byID["data:skill-go"]
+	assert.Equal(t, SearchSkill, skill.Type)
+	assert.Equal(t, "/#skill-go", skill.URL)
+	assert.Equal(t, "Languages · used at Ecreativeworks, Acme", skill.Description)
+	assert.Equal(t, []string{"golang"}, skill.Tags)
+
+	// Technologies that are not declared skills are searchable too.
+	assert.Equal(t, SearchSkill, byID["data:skill-terraform"].Type)
+
+	cert := byID["data:certification-aws-certified-developer"]
+	assert.Equal(t, SearchCertification, cert.Type)
+	assert.Equal(t, "Issued March 2023", cert.Description)
+
+	idx := NewSearchIndex(SearchBoosts{})
+	idx.Sync(docs)
+	results := idx.Search("keycloak", SearchOptions{Types: []string{SearchExperience}})
+	require.Len(t, results, 1)
+	assert.Equal(t, exp.ID, results[0].ID)
+
+	results = idx.Search("keycloak", SearchOptions{Types: []string{SearchSkill}})
+	require.Len(t, results, 1)
+	assert.Equal(t, "data:skill-keycloak", results[0].ID)
+}
+
+func TestDataSearchDocumentsAnchors(t *testing.T) {
+	data, err := LoadData([]byte(skillsYAML + `
+certifications:
+  - name: AWS Certified Developer
+    issued: March 2023
+`))
+	require.NoError(t, err)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	config := DefaultServerConfig()
+	config.Theme = "green-nebula-terminal"
+	server := NewServer(config, nil)
+	var buf bytes.Buffer
+	require.NoError(t, server.themes.Render(&buf, config.Theme, "index", newHomePage(data, now)))
+	page := buf.String()
+
+	// Every hit deep links to an entry rendered on the home page.
+	for _, doc := range DataSearchDocuments(data, now) {
+		anchor := strings.TrimPrefix(doc.URL, "/#")
+		assert.Contains(t, page, `id="`+anchor+`"`, doc.ID)
+	}
+}
+
+func TestPageSearchDocumentProject(t *testing.T) {
+	page := testPage("projects/b2mfg/index.md", joinLines(
+		"---",
+		"title: B2MFG",
+		"link: https://b2mfg.com/",
+		"---",
+		"A client website.",
+	))
+	doc := pageSearchDocument(page)
+	assert.Equal(t, SearchProject, doc.Type)
+	assert.Contains(t, doc.Body, "https://b2mfg.com/")
+
+	assert.Equal(t, SearchPost, pageSearchDocument(testPage("blog/post.md", "---\ntitle: Post\n---\n")).Type)
+	assert.Equal(t, SearchPage, pageSearchDocument(testPage("about.md", "---\ntitle: About\n---\n")).Type)
+}
+
+func TestHandleSearchProjects(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), nil)
+
+	req := httptest.NewRequest("GET", "/search?q=b2mfg&type=project", nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Results []struct {
+			Type string `json:"type"`
+			URL  string `json:"url"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	require.NotEmpty(t, body.Results)
+	for _, result := range body.Results {
+		assert.Equal(t, SearchProject, result.Type)
+	}
+}
@@ -0,0 +1,204 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Series is an ordered group of posts, such as a multi-part article.
+type Series struct {
+	// Name is the series name as written in front matter.
+	Name string
+	// Key is the normalized name used for lookups and URLs.
+	Key   string
+	Parts []*Page
+}
+
+// SeriesPosition locates a page within its series.
+type SeriesPosition struct {
+	Series *Series
+	Index  int
+}
+
+// SeriesName returns the series the page belongs to, or "". A plain
+// string is taken whole so names may contain commas; a list uses its
+// first entry.
+func (p *Page) SeriesName() string {
+	if name, ok := p.Meta["series"].(string); ok && !strings.HasPrefix(name, "[") {
+		return strings.TrimSpace(name)
+	}
+	names := p.Terms("series")
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
+
+// SeriesWeight returns the series_weight front matter value used to
+// order parts. Parts without one are ordered by date after weighted
+// parts.
+func (p *Page) SeriesWeight() (int, bool) {
+	switch v := p.Meta["series_weight"].(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	}
+	return 0, false
+}
+
+// buildSeries groups the regular pages of the index into series.
+func (idx *ContentIndex) buildSeries() {
+	idx.series = make(map[string]*Series)
+	for _, page := range idx.pages {
+		name := page.SeriesName()
+		if page.Kind != KindPage || name == "" {
+			continue
+		}
+		key := NormalizeTerm(name, nil)
+		s := idx.series[key]
+		if s == nil {
+			s = &Series{Name: name, Key: key}
+			idx.series[key] = s
+		}
+		s.Parts = append(s.Parts, page)
+	}
+	for _, s := range idx.series {
+		sortParts(s.Parts)
+	}
+}
+
+// sortParts orders parts by series_weight, then date, then path.
+func sortParts(parts []*Page) {
+	sort.SliceStable(parts, func(i, j int) bool {
+		wi, iok := parts[i].SeriesWeight()
+		wj, jok := parts[j].SeriesWeight()
+		if iok != jok {
+			return iok
+		}
+		if wi != wj {
+			return wi < wj
+		}
+		di, dj := parts[i].Front().Date, parts[j].Front().Date
+		if !di.Equal(dj) {
+			return di.Before(dj)
+		}
+		return parts[i].Path < parts[j].Path
+	})
+}
+
+// Series returns the series with the given name, or nil.
+func (idx *ContentIndex) Series(name string) *Series {
+	return idx.series[NormalizeTerm(name, nil)]
+}
+
+// AllSeries returns every series ordered by key.
+func (idx *ContentIndex) AllSeries() []*Series {
+	all := make([]*Series, 0, len(idx.series))
+	for _, s := range idx.series {
+		all = append(all, s)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Key < all[j].Key
+	})
+	return all
+}
+
+// Visible returns a copy of the series holding only the parts visible
+// at now. Part numbers follow the visible parts.
+func (s *Series) Visible(opts PublishOptions, now time.Time) *Series {
+	return &Series{Name: s.Name, Key: s.Key, Parts: opts.Filter(s.Parts, now)}
+}
+
+// Position returns where page sits in the series.
+func (s *Series) Position(page *Page) (SeriesPosition, bool) {
+	for i, part := range s.Parts {
+		if part == page {
+			return SeriesPosition{Series: s, Index: i}, true
+		}
+	}
+	return SeriesPosition{}, false
+}
+
+// URL returns the landing endpoint of the series.
+func (s *Series) URL() string {
+	return "/api/series/" + s.Key
+}
+
+// Part returns the 1-based part number.
+func (p SeriesPosition) Part() int {
+	return p.Index + 1
+}
+
+// Total returns the number of parts in the series.
+func (p SeriesPosition) Total() int {
+	return len(p.Series.Parts)
+}
+
+// Prev returns the previous part, or nil for the first part.
+func (p SeriesPosition) Prev() *Page {
+	if p.Index == 0 {
+		return nil
+	}
+	return p.Series.Parts[p.Index-1]
+}
+
+// Next returns the next part, or nil for the last part.
+func (p SeriesPosition) Next() *Page {
+	if p.Index+1 >= len(p.Series.Parts) {
+		return nil
+	}
+	return p.Series.Parts[p.Index+1]
+}
+
+// String returns a label such as "Part 2 of 4".
+func (p SeriesPosition) String() string {
+	return fmt.Sprintf("Part %d of %d", p.Part(), p.Total())
+}
+
+// GetSeries returns the visible parts of the named series.
+func (m *AssetManager) GetSeries(ctx context.Context, name string) (*Series, error) {
+	idx, err := m.Index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s := idx.Series(name)
+	if s == nil {
+		return nil, fmt.Errorf("series %q does not exist", name)
+	}
+	return s.Visible(m.Publish, m.now()), nil
+}
+
+// ListSeries returns every series with at least one visible part.
+func (m *AssetManager) ListSeries(ctx context.Context) ([]*Series, error) {
+	idx, err := m.Index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var all []*Series
+	for _, s := range idx.AllSeries() {
+		if visible := s.Visible(m.Publish, m.now()); len(visible.Parts) > 0 {
+			all = append(all, visible)
+		}
+	}
+	return all, nil
+}
+
+// SeriesPosition returns where page sits among the visible parts of its
+// series.
+func (m *AssetManager) SeriesPosition(ctx context.Context, page *Page) (SeriesPosition, bool) {
+	name := page.SeriesName()
+	if name == "" {
+		return SeriesPosition{}, false
+	}
+	s, err := m.GetSeries(ctx, name)
+	if err != nil {
+		return SeriesPosition{}, false
+	}
+	return s.Position(page)
+}
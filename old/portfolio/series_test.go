@@ -0,0 +1,94 @@
+package portfolio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seriesFixture() fstest.MapFS {
+	post := func(front string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte("---\n" + front + "\n---\nBody\n")}
+	}
+	return fstest.MapFS{
+		"hugo.yaml":                  {Data: []byte("title: Test\n")},
+		"content/blog/_index.md":     post("title: Blog\nseries: Ignored"),
+		"content/blog/intro.md":      post("title: Intro\ndate: 2025-03-01\nseries: Building a Keg\nseries_weight: 1"),
+		"content/blog/storage.md":    post("title: Storage\ndate: 2025-01-01\nseries: building-a-keg\nseries_weight: 2"),
+		"content/blog/links.md":      post("title: Links\ndate: 2025-02-01\nseries: [Building a Keg]"),
+		"content/blog/wip.md":        post("title: WIP\ndate: 2025-02-15\nseries: Building a Keg\ndraft: true"),
+		"content/blog/one, two.md":   post("title: Commas\ndate: 2025-01-01\nseries: One, Two"),
+		"content/blog/standalone.md": post("title: Standalone\ndate: 2025-01-01"),
+	}
+}
+
+func TestContentIndexSeries(t *testing.T) {
+	idx, err := NewContentIndex(context.Background(), seriesFixture(), "content")
+	require.NoError(t, err)
+
+	all := idx.AllSeries()
+	require.Len(t, all, 2)
+	assert.Equal(t, "building-a-keg", all[0].Key)
+	assert.Equal(t, "one-two", all[1].Key)
+
+	// Weighted parts come first, then the rest by date.
+	series := idx.Series("Building A Keg")
+	require.NotNil(t, series)
+	var titles []string
+	for _, part := range series.Parts {
+		titles = append(titles, part.Title())
+	}
+	assert.Equal(t, []string{"Intro", "Storage", "Links", "WIP"}, titles)
+
+	assert.Nil(t, idx.Series("nope"))
+	assert.Empty(t, idx.ByPath("blog/standalone").SeriesName())
+}
+
+func TestSeriesPosition(t *testing.T) {
+	idx, err := NewContentIndex(context.Background(), seriesFixture(), "content")
+	require.NoError(t, err)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Drafts are not counted as parts.
+	series := idx.Series("building-a-keg").Visible(PublishOptions{}, now)
+	require.Len(t, series.Parts, 3)
+
+	pos, ok := series.Position(idx.ByPath("blog/storage"))
+	require.True(t, ok)
+	assert.Equal(t, "Part 2 of 3", pos.String())
+	assert.Equal(t, "Intro", pos.Prev().Title())
+	assert.Equal(t, "Links", pos.Next().Title())
+
+	first, ok := series.Position(idx.ByPath("blog/intro"))
+	require.True(t, ok)
+	assert.Nil(t, first.Prev())
+	last, ok := series.Position(idx.ByPath("blog/links"))
+	require.True(t, ok)
+	assert.Nil(t, last.Next())
+
+	_, ok = series.Position(idx.ByPath("blog/wip"))
+	assert.False(t, ok)
+	_, ok = series.Position(idx.ByPath("blog/standalone"))
+	assert.False(t, ok)
+}
+
+func TestHandleSeries(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), nil)
+
+	req := httptest.NewRequest("GET", "/api/series", nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"series":[]}`, w.Body.String())
+
+	req = httptest.NewRequest("GET", "/api/series/nope", nil)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
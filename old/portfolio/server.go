@@ -3,14 +3,19 @@ package portfolio
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"html/template"
 	"io/fs"
 	"log/slog"
+	"net"
 	"net/http"
+	"net/netip"
+	"net/url"
 	"os"
-	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,6 +32,31 @@ type ServerConfig struct {
 	MaxHeaderBytes int
 
 	Theme string
+	// BaseURL is the public site URL. The baseURL of hugo.yaml is used
+	// when empty.
+	BaseURL string
+
+	// Preview serves drafts, future and expired pages.
+	Preview bool
+	// PreviewKey signs preview links. A random key is used when empty.
+	PreviewKey string
+	// PreviewRevocations is the file keeping revoked preview links
+	// across restarts. Revocations are kept in memory when empty.
+	PreviewRevocations string
+	// AdminToken is the bearer token for /api/admin. Admin routes are
+	// disabled when empty.
+	AdminToken string
+	// SiteDir serves content, data and themes from a directory instead
+	// of the embedded assets. Edits are picked up every WatchInterval,
+	// or right away with /api/admin/reload.
+	SiteDir string
+	// WatchInterval is how often SiteDir is checked for changes. Zero
+	// uses two seconds.
+	WatchInterval time.Duration
+	// TrustedProxies are the addresses or CIDR ranges of the reverse
+	// proxies whose X-Forwarded-Proto header is honored. The header is
+	// ignored from any other client.
+	TrustedProxies []string
 }
 
 // DefaultServerConfig returns sensible defaults for ServerConfig
@@ -38,6 +68,7 @@ func DefaultServerConfig() ServerConfig {
 		IdleTimeout:    120 * time.Second,
 		MaxHeaderBytes: 1 << 20, // 1 MB
 		Theme:          DefaultTheme,
+		BaseURL:        "https://jlrickert.me",
 	}
 }
 
@@ -46,6 +77,12 @@ type Server struct {
 	config       ServerConfig
 	router       *chi.Mux
 	assetManager *AssetManager
+	themes       *ThemeManager
+	scheduler    *Scheduler
+	watcher      *Watcher
+	proxies      []netip.Prefix
+	previews     *PreviewSigner
+	site         *SiteConfig
 	logger       *slog.Logger
 	httpServer   *http.Server
 }
@@ -74,6 +111,82 @@ func NewServer(config ServerConfig, logger *slog.Logger) *Server {
 	}
 
 	server.assetManager.Logger = logger
+	for _, proxy := range config.TrustedProxies {
+		prefix, err := parseProxy(proxy)
+		if err != nil {
+			logger.Error("ignoring invalid trusted proxy", "proxy", proxy, "error", err)
+			continue
+		}
+		server.proxies = append(server.proxies, prefix)
+	}
+	if config.SiteDir != "" {
+		server.assetManager.Assets = os.DirFS(config.SiteDir)
+	}
+	if config.Preview {
+		server.assetManager.Publish = PreviewOptions
+	}
+	site, err := server.assetManager.Site()
+	if err != nil {
+		logger.Error("failed to load site config", "error", err)
+		site, _ = NewSiteConfig(map[string]any{})
+	}
+	server.site = site
+	if server.config.BaseURL == "" {
+		server.config.BaseURL = site.BaseURL
+	}
+	server.assetManager.Related = site.RelatedOptions()
+
+	if _, err := server.assetManager.Index(context.Background()); err != nil {
+		logger.Error("failed to build content index", "error", err)
+	}
+
+	themeParams, _ := site.Params["theme"].(map[string]any)
+	themes, err := NewThemeManager(server.assetManager.Assets, config.Theme, themeParams, server.siteFuncs())
+	if err != nil {
+		logger.Error("failed to load themes", "error", err)
+	}
+	server.themes = themes
+
+	previews, err := NewPreviewSigner([]byte(config.PreviewKey))
+	if err != nil {
+		logger.Error("failed to create preview signer", "error", err)
+	} else if config.PreviewRevocations != "" {
+		if err := previews.Persist(config.PreviewRevocations); err != nil {
+			logger.Error("failed to load preview revocations", "error", err)
+		}
+	} else if config.PreviewKey != "" {
+		logger.Warn("preview revocations are lost on restart; set a revocations file")
+	}
+	server.previews = previews
+
+	server.scheduler = NewScheduler(server.assetManager.AllPages, logger)
+	server.scheduler.OnChange(func() {
+		logger.Info("publication state changed")
+		server.assetManager.SyncTaxonomies()
+		if err := server.assetManager.SyncSearch(context.Background()); err != nil {
+			logger.Error("failed to sync search index", "error", err)
+		}
+	})
+	if err := server.scheduler.Start(context.Background()); err != nil {
+		logger.Error("failed to start publication scheduler", "error", err)
+	}
+
+	if config.SiteDir != "" {
+		interval := config.WatchInterval
+		if interval <= 0 {
+			interval = 2 * time.Second
+		}
+		server.watcher = NewWatcher(server.assetManager.Assets, interval, logger,
+			"content", "data", "themes", "hugo.yaml")
+		server.watcher.OnChange(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := server.reload(ctx); err != nil {
+				logger.Error("failed to reload content", "error", err)
+			}
+		})
+		server.watcher.Start()
+	}
 
 	server.setupRoutes()
 	server.setupHTTPServer()
@@ -83,22 +196,59 @@ func NewServer(config ServerConfig, logger *slog.Logger) *Server {
 
 // setupRoutes configures all HTTP routes
 func (s *Server) setupRoutes() {
+	s.router.Use(s.resolveTheme)
+
 	s.router.Get("/", s.handleGetHome)
 	s.router.Get("/posts", s.handleListPosts)
 	s.router.Get("/posts/{slug}", s.handleGetPost)
 	s.router.Get("/example", s.handleExample)
+	s.router.Get("/preview/{token}", s.handlePreview)
+	s.router.Get("/archive", s.handleArchive)
+	s.router.Get("/search", s.handleSearch)
+
+	// Blog feeds at /blog/index.xml, /blog/atom.xml and /blog/feed.json
+	for _, format := range FeedFormats {
+		s.router.Get("/blog/"+format.File(), s.handleFeed("", format))
+	}
 
-	// Static files
+	// Taxonomy pages such as /tags and /tags/{term}, with term feeds
+	for _, name := range s.site.TaxonomyNames() {
+		s.router.Get("/"+name, s.handleTaxonomy(name))
+		s.router.Get("/"+name+"/{term}", s.handleTerm(name))
+		for _, format := range FeedFormats {
+			s.router.Get("/"+name+"/{term}/"+format.File(), s.handleFeed(name, format))
+		}
+	}
+
+	// Static files, with the code highlighting stylesheet generated
+	s.router.Get("/static/css/highlight.css", s.handleHighlightCSS)
 	s.router.Get("/static/*", s.handleStatic)
 
 	// API routes
 	s.router.Route("/api", func(r chi.Router) {
 		r.Get("/data", s.handleGetData)
+		r.Get("/site", s.handleGetSite)
+		r.Get("/skills", s.handleListSkills)
+		r.Get("/skills/{name}", s.handleGetSkill)
+		r.Get("/related/{slug}", s.handleRelated)
+		r.Get("/series", s.handleListSeries)
+		r.Get("/series/{name}", s.handleGetSeries)
+		r.Get("/archive", s.handleGetArchive)
+		r.Get("/stats", s.handleWritingStats)
+		r.Get("/timeline", s.handleTimeline)
+		r.Get("/timeline.ics", s.handleTimelineICal)
 		// htmx partial endpoints
 		r.Get("/posts/partial", s.handlePostsPartial)
 		r.Get("/experience/partial", s.handleExperiencePartial)
 		r.Get("/skills/partial", s.handleSkillsPartial)
 		r.Post("/theme", s.handleThemeSwitch)
+
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(s.requireAdmin)
+			r.Post("/previews", s.handleCreatePreview)
+			r.Delete("/previews/{id}", s.handleRevokePreview)
+			r.Post("/reload", s.handleReload)
+		})
 	})
 	//
 	// Health check
@@ -129,6 +279,10 @@ func (s *Server) Start() error {
 // Shutdown gracefully shuts down the server with timeout
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("shutting down server")
+	s.scheduler.Stop()
+	if s.watcher != nil {
+		s.watcher.Stop()
+	}
 	return s.httpServer.Shutdown(ctx)
 }
 
@@ -167,15 +321,794 @@ func (s *Server) handleGetData(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(data)
 }
 
-// handleListPosts handles GET /api/posts requests
-func (s *Server) handleListPosts(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement post listing with pagination
+// handleGetSite handles GET /api/site requests with the site title,
+// menus and params from hugo.yaml.
+func (s *Server) handleGetSite(w http.ResponseWriter, r *http.Request) {
+	menus := make(map[string][]MenuEntry, len(s.site.Menus))
+	for name := range s.site.Menus {
+		menus[name] = s.menu(r.Context(), name)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
 	json.NewEncoder(w).Encode(map[string]any{
-		"posts": []any{},
+		"title":        s.site.Title,
+		"baseURL":      s.config.BaseURL,
+		"languageCode": s.site.LanguageCode,
+		"menus":        menus,
+		"params":       s.site.Params,
 	})
 }
 
+// menu returns a site menu with page references resolved.
+func (s *Server) menu(ctx context.Context, name string) []MenuEntry {
+	idx, err := s.assetManager.Index(ctx)
+	if err != nil {
+		s.logger.Error("failed to resolve menu", "menu", name, "error", err)
+	}
+	return s.site.Menu(name, idx)
+}
+
+// siteFuncs exposes the site config to templates as site, menu and
+// param, along with the related, series and archive lookups.
+func (s *Server) siteFuncs() template.FuncMap {
+	return template.FuncMap{
+		"site": func() *SiteConfig {
+			return s.site
+		},
+		"menu": func(name string) []MenuEntry {
+			return s.menu(context.Background(), name)
+		},
+		"param": func(key string) any {
+			return s.site.Param(key)
+		},
+		"related": func(page *Page, n int) []*Page {
+			related, err := s.assetManager.RelatedPages(context.Background(), page.Path, n)
+			if err != nil {
+				s.logger.Error("failed to find related pages", "path", page.Path, "error", err)
+			}
+			pages := make([]*Page, 0, len(related))
+			for _, r := range related {
+				pages = append(pages, r.Page)
+			}
+			return pages
+		},
+		"series": func(page *Page) *SeriesPosition {
+			pos, ok := s.assetManager.SeriesPosition(context.Background(), page)
+			if !ok {
+				return nil
+			}
+			return &pos
+		},
+		"archive": func() Archive {
+			archive, err := s.assetManager.Archive(context.Background())
+			if err != nil {
+				s.logger.Error("failed to build archive", "error", err)
+			}
+			return archive
+		},
+	}
+}
+
+// handleListSkills handles GET /api/skills requests
+func (s *Server) handleListSkills(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	data, err := s.assetManager.GetData(ctx)
+	if err != nil {
+		s.logger.Error("failed to get data for skills", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "failed to retrieve skills",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	json.NewEncoder(w).Encode(map[string]any{
+		"skills": NewSkillIndex(data, time.Now()).All(),
+	})
+}
+
+// handleGetSkill handles GET /api/skills/{name} requests and reports
+// where and for how long a skill has been used.
+func (s *Server) handleGetSkill(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	data, err := s.assetManager.GetData(ctx)
+	if err != nil {
+		s.logger.Error("failed to get data for skill", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "failed to retrieve skills",
+		})
+		return
+	}
+
+	profile := NewSkillIndex(data, time.Now()).Get(name)
+	if profile == nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "skill not found",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	json.NewEncoder(w).Encode(profile)
+}
+
+// timelineFilter parses the type and year query parameters. Multiple
+// types may be given as repeated or comma separated values.
+func timelineFilter(r *http.Request) (TimelineFilter, error) {
+	var filter TimelineFilter
+	for _, value := range r.URL.Query()["type"] {
+		for kind := range strings.SplitSeq(value, ",") {
+			if kind = strings.TrimSpace(kind); kind != "" {
+				filter.Types = append(filter.Types, TimelineEventType(kind))
+			}
+		}
+	}
+	if year := r.URL.Query().Get("year"); year != "" {
+		y, err := strconv.Atoi(year)
+		if err != nil {
+			return filter, fmt.Errorf("invalid year %q", year)
+		}
+		filter.Year = y
+	}
+	return filter, nil
+}
+
+// handleTimeline handles GET /api/timeline requests
+// Supports query parameters: type (event type), year (overlapping year)
+func (s *Server) handleTimeline(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	filter, err := timelineFilter(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	timeline, err := s.assetManager.GetTimeline(ctx)
+	if err != nil {
+		s.logger.Error("failed to build timeline", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "failed to build timeline",
+		})
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	json.NewEncoder(w).Encode(map[string]any{
+		"events": timeline.Filter(filter),
+	})
+}
+
+// handleTimelineICal handles GET /api/timeline.ics requests
+func (s *Server) handleTimelineICal(w http.ResponseWriter, r *http.Request) {
+	filter, err := timelineFilter(r)
+	if err != nil {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	timeline, err := s.assetManager.GetTimeline(ctx)
+	if err != nil {
+		s.logger.Error("failed to build timeline", "error", err)
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, "failed to build timeline")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := timeline.Filter(filter).WriteICal(&buf, s.config.BaseURL, time.Now()); err != nil {
+		s.logger.Error("failed to write timeline calendar", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.Write(buf.Bytes())
+}
+
+// handleRelated handles GET /api/related/{slug} requests
+// Supports query parameters: limit (default 5, max 10)
+func (s *Server) handleRelated(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	limit := 5
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > 10 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"error": "limit must be between 1 and 10",
+			})
+			return
+		}
+		limit = n
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	slug := chi.URLParam(r, "slug")
+	related, err := s.assetManager.RelatedPages(ctx, slug, limit)
+	if err != nil {
+		s.logger.Error("failed to find related pages", "slug", slug, "error", err)
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "post not found",
+		})
+		return
+	}
+
+	type relatedSummary struct {
+		PostSummary
+		Score float64 `json:"score"`
+	}
+	summaries := make([]relatedSummary, 0, len(related))
+	for _, r := range related {
+		summaries = append(summaries, relatedSummary{summarizePost(r.Page), r.Score})
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	json.NewEncoder(w).Encode(map[string]any{
+		"slug":    slug,
+		"related": summaries,
+	})
+}
+
+// SeriesSummary is the listing representation of a series.
+type SeriesSummary struct {
+	Name  string `json:"name"`
+	Key   string `json:"key"`
+	Parts int    `json:"parts"`
+	URL   string `json:"url"`
+}
+
+func summarizeSeries(series *Series) SeriesSummary {
+	return SeriesSummary{
+		Name:  series.Name,
+		Key:   series.Key,
+		Parts: len(series.Parts),
+		URL:   series.URL(),
+	}
+}
+
+// SeriesNav is a page's place in its series for previous/next
+// navigation.
+type SeriesNav struct {
+	SeriesSummary
+	Part int          `json:"part"`
+	Prev *PostSummary `json:"prev,omitempty"`
+	Next *PostSummary `json:"next,omitempty"`
+}
+
+func seriesNav(pos SeriesPosition) *SeriesNav {
+	nav := &SeriesNav{SeriesSummary: summarizeSeries(pos.Series), Part: pos.Part()}
+	if prev := pos.Prev(); prev != nil {
+		summary := summarizePost(prev)
+		nav.Prev = &summary
+	}
+	if next := pos.Next(); next != nil {
+		summary := summarizePost(next)
+		nav.Next = &summary
+	}
+	return nav
+}
+
+// handleListSeries handles GET /api/series requests
+func (s *Server) handleListSeries(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	all, err := s.assetManager.ListSeries(ctx)
+	if err != nil {
+		s.logger.Error("failed to list series", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "failed to load series",
+		})
+		return
+	}
+
+	summaries := make([]SeriesSummary, 0, len(all))
+	for _, series := range all {
+		summaries = append(summaries, summarizeSeries(series))
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	json.NewEncoder(w).Encode(map[string]any{
+		"series": summaries,
+	})
+}
+
+// handleGetSeries handles GET /api/series/{name} requests, the landing
+// page of a series listing its parts in order.
+func (s *Server) handleGetSeries(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	name := chi.URLParam(r, "name")
+	series, err := s.assetManager.GetSeries(ctx, name)
+	if err == nil && len(series.Parts) == 0 {
+		err = fmt.Errorf("series %q has no published parts", name)
+	}
+	if err != nil {
+		s.logger.Error("failed to get series", "name", name, "error", err)
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "series not found",
+		})
+		return
+	}
+
+	parts := make([]PostSummary, 0, len(series.Parts))
+	for _, page := range series.Parts {
+		parts = append(parts, summarizePost(page))
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	json.NewEncoder(w).Encode(map[string]any{
+		"name":  series.Name,
+		"key":   series.Key,
+		"url":   series.URL(),
+		"parts": parts,
+	})
+}
+
+// handleGetArchive handles GET /api/archive requests
+func (s *Server) handleGetArchive(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	archive, err := s.assetManager.Archive(ctx)
+	if err != nil {
+		s.logger.Error("failed to build archive", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "failed to build archive",
+		})
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=600")
+	json.NewEncoder(w).Encode(archive)
+}
+
+// handleWritingStats handles GET /api/stats requests
+func (s *Server) handleWritingStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	stats, err := s.assetManager.WritingStats(ctx)
+	if err != nil {
+		s.logger.Error("failed to compute writing stats", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "failed to compute writing stats",
+		})
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=600")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// handleArchive handles GET /archive requests, rendering the archive
+// page with the writing stats.
+// Supports query parameters: format (json)
+func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	archive, err := s.assetManager.Archive(ctx)
+	var stats WritingStats
+	if err == nil {
+		stats, err = s.assetManager.WritingStats(ctx)
+	}
+	if err != nil {
+		s.logger.Error("failed to build archive", "error", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "failed to build archive",
+		})
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=600")
+	if wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"archive": archive,
+			"stats":   stats,
+		})
+		return
+	}
+
+	html, err := s.renderTemplate(ctx, "archive", map[string]any{
+		"Title":   "Archive",
+		"Archive": archive,
+		"Stats":   stats,
+	})
+	if err != nil {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, "<h1>Error</h1><p>Failed to render page</p>")
+		return
+	}
+	w.Header().Add("Vary", "Cookie")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(html)
+}
+
+// handleSearch handles GET /search requests
+// Supports query parameters: q, limit (default 10, max 50) and type
+// (repeatable)
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	query := r.URL.Query().Get("q")
+	if strings.TrimSpace(query) == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "query parameter q is required",
+		})
+		return
+	}
+
+	opts := SearchOptions{Types: r.URL.Query()["type"]}
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > MaxSearchLimit {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"error": fmt.Sprintf("limit must be between 1 and %d", MaxSearchLimit),
+			})
+			return
+		}
+		opts.Limit = n
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	search, err := s.assetManager.Search(ctx)
+	if err != nil {
+		s.logger.Error("failed to build search index", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "search is unavailable",
+		})
+		return
+	}
+
+	var suggestions []string
+	if fields := strings.Fields(query); !strings.HasSuffix(query, " ") {
+		suggestions = search.Suggest(fields[len(fields)-1], 5)
+	}
+
+	results := search.Search(query, opts)
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	json.NewEncoder(w).Encode(map[string]any{
+		"query":       query,
+		"total":       len(results),
+		"results":     results,
+		"suggestions": suggestions,
+	})
+}
+
+// TermSummary is the listing representation of a taxonomy term.
+type TermSummary struct {
+	Name  string `json:"name"`
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+	URL   string `json:"url"`
+}
+
+func summarizeTerm(taxonomy string, term *Term) TermSummary {
+	return TermSummary{
+		Name:  term.Name,
+		Key:   term.Key,
+		Count: term.Count,
+		URL:   "/" + taxonomy + "/" + url.PathEscape(term.Key) + "/",
+	}
+}
+
+// wantsJSON reports whether the client asked for JSON through the
+// Accept header or ?format=json.
+func wantsJSON(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "json" {
+		return true
+	}
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+// handleTaxonomy returns a handler for GET /{taxonomy} requests listing
+// every term with its page count, most used first.
+// Supports query parameters: sort (count or name), format (json)
+func (s *Server) handleTaxonomy(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		taxonomies, err := s.assetManager.Taxonomies(ctx)
+		if err != nil {
+			s.logger.Error("failed to build taxonomies", "taxonomy", name, "error", err)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"error": "failed to list terms",
+			})
+			return
+		}
+
+		tax, ok := taxonomies[name]
+		if !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"error": "taxonomy not found",
+			})
+			return
+		}
+		terms := tax.Terms()
+		if r.URL.Query().Get("sort") == "name" {
+			terms = tax.Alphabetical()
+		}
+		summaries := make([]TermSummary, 0, len(terms))
+		for _, term := range terms {
+			summaries = append(summaries, summarizeTerm(name, term))
+		}
+
+		w.Header().Set("Cache-Control", "public, max-age=600")
+		if wantsJSON(r) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{
+				"taxonomy": name,
+				"terms":    summaries,
+			})
+			return
+		}
+
+		html, err := s.renderTemplate(ctx, "taxonomy", map[string]any{
+			"Title":    humanize(name),
+			"Taxonomy": name,
+			"Terms":    summaries,
+		})
+		if err != nil {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, "<h1>Error</h1><p>Failed to render page</p>")
+			return
+		}
+		w.Header().Add("Vary", "Cookie")
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(html)
+	}
+}
+
+// handleTerm returns a handler for GET /{taxonomy}/{term} requests
+// listing the pages of a term, newest first.
+func (s *Server) handleTerm(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		taxonomies, err := s.assetManager.Taxonomies(ctx)
+		if err != nil {
+			s.logger.Error("failed to build taxonomies", "taxonomy", name, "error", err)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"error": "failed to list pages",
+			})
+			return
+		}
+
+		tax, ok := taxonomies[name]
+		if !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"error": "taxonomy not found",
+			})
+			return
+		}
+		term := tax.Get(chi.URLParam(r, "term"))
+		if term == nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"error": "term not found",
+			})
+			return
+		}
+
+		pages := make([]PostSummary, 0, len(term.Pages))
+		for _, page := range term.Pages {
+			pages = append(pages, summarizePost(page))
+		}
+
+		w.Header().Set("Cache-Control", "public, max-age=600")
+		if wantsJSON(r) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{
+				"taxonomy": name,
+				"term":     summarizeTerm(name, term),
+				"pages":    pages,
+			})
+			return
+		}
+
+		html, err := s.renderTemplate(ctx, "term", map[string]any{
+			"Taxonomy": humanize(name),
+			"Term":     summarizeTerm(name, term),
+			"Pages":    pages,
+		})
+		if err != nil {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, "<h1>Error</h1><p>Failed to render page</p>")
+			return
+		}
+		w.Header().Add("Vary", "Cookie")
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(html)
+	}
+}
+
+// handleFeed returns a handler for the blog feed, or the feed of a
+// taxonomy term when taxonomy is set. Conditional requests are answered
+// from the ETag and Last-Modified of the feed.
+func (s *Server) handleFeed(taxonomy string, format FeedFormat) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		title := s.site.Title
+		link := "/blog/"
+		var pages []*Page
+		var err error
+		if taxonomy == "" {
+			pages, err = s.assetManager.ListSection(ctx, "blog")
+		} else {
+			var taxonomies Taxonomies
+			taxonomies, err = s.assetManager.Taxonomies(ctx)
+			if err == nil {
+				var term *Term
+				if tax, ok := taxonomies[taxonomy]; ok {
+					term = tax.Get(chi.URLParam(r, "term"))
+				}
+				if term == nil {
+					w.Header().Set("Content-Type", "text/plain")
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, "feed not found")
+					return
+				}
+				title = fmt.Sprintf("%s: %s", title, term.Name)
+				link = summarizeTerm(taxonomy, term).URL
+				pages = term.Pages
+			}
+		}
+		if err != nil {
+			s.logger.Error("failed to build feed", "taxonomy", taxonomy, "error", err)
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, "failed to build feed")
+			return
+		}
+
+		base, _ := url.Parse(s.config.BaseURL)
+		if base == nil {
+			base = &url.URL{}
+		}
+		feed := NewFeed(pages, s.config.BaseURL, DefaultFeedLimit)
+		feed.Title = title
+		feed.Description, _ = s.site.Param("description").(string)
+		feed.Language = s.site.LanguageCode
+		feed.Author, _ = s.site.Param("author").(string)
+		feed.Link = resolveURL(base, link)
+		feed.Self = resolveURL(base, link+format.File())
+
+		var buf bytes.Buffer
+		if err := feed.Write(&buf, format); err != nil {
+			s.logger.Error("failed to write feed", "format", format, "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", format.ContentType())
+		w.Header().Set("Cache-Control", "public, max-age=600")
+		w.Header().Set("ETag", fmt.Sprintf(`"%x"`, sha256.Sum256(buf.Bytes())))
+		http.ServeContent(w, r, "", feed.Updated, bytes.NewReader(buf.Bytes()))
+	}
+}
+
+// handleListPosts handles GET /posts requests
+// Supports query parameters: tag, year, draft, sort, order, page,
+// per_page and cursor. Pagination links are sent in the Link header.
+func (s *Server) handleListPosts(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	query, err := ParsePostQuery(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+	// Drafts are only served in preview mode, so asking for them
+	// elsewhere is refused rather than answered with an empty list.
+	if query.Draft != DraftExclude && !s.assetManager.Publish.BuildDrafts {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "drafts are only listed in preview mode",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	pages, err := s.assetManager.ListSection(ctx, "blog")
+	if err != nil {
+		s.logger.Error("failed to list posts", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "failed to list posts",
+		})
+		return
+	}
+
+	list, err := ListPosts(pages, query)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if link := list.LinkHeader(r.URL, query.Cursor != ""); link != "" {
+		w.Header().Set("Link", link)
+	}
+	w.Header().Set("Cache-Control", "public, max-age=600")
+	json.NewEncoder(w).Encode(list)
+}
+
 // handleGetPost handles GET /api/posts/{slug} requests
 func (s *Server) handleGetPost(w http.ResponseWriter, r *http.Request) {
 	slug := chi.URLParam(r, "slug")
@@ -192,6 +1125,12 @@ func (s *Server) handleGetPost(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	post, err := s.assetManager.GetPage(ctx, slug)
+	preview := false
+	if token := r.URL.Query().Get("preview"); token != "" {
+		if page, ok := s.previewPage(ctx, slug, token); ok {
+			post, err, preview = page, nil, true
+		}
+	}
 	if err != nil {
 		s.logger.Error(
 			"failed to get post",
@@ -205,56 +1144,254 @@ func (s *Server) handleGetPost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var nav *SeriesNav
+	if pos, ok := s.assetManager.SeriesPosition(ctx, post); ok {
+		nav = seriesNav(pos)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Cache-Control", "public, max-age=3600")
+	if preview {
+		w.Header().Set("Cache-Control", "private, no-store")
+		w.Header().Set("X-Robots-Tag", "noindex, nofollow")
+	} else {
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+	}
 	json.NewEncoder(w).Encode(map[string]any{
-		"slug":    post.Path,
-		"title":   post.Title(),
-		"content": string(post.Content),
-		"date":    post.Date(),
-		"tags":    post.Tags(),
+		"preview":     preview,
+		"slug":        post.Slug(),
+		"path":        post.Path,
+		"url":         post.URL(),
+		"title":       post.Title(),
+		"content":     string(post.Content),
+		"date":        post.Date(),
+		"tags":        post.Tags(),
+		"wordCount":   post.WordCount(),
+		"readingTime": post.ReadingTime(),
+		"toc":         post.TableOfContents(),
+		"hasMermaid":  post.HasMermaid(),
+		"series":      nav,
 	})
 }
 
-func (s *Server) renderTemplate(name string, data any) ([]byte, error) {
-	// Load base template content
-	baseContent, err := s.assetManager.GetTemplateContent(s.config.Theme, "_base")
+// previewPage resolves ref with a preview token. The token must have
+// been minted for the page ref resolves to.
+func (s *Server) previewPage(ctx context.Context, ref, raw string) (*Page, bool) {
+	if s.previews == nil {
+		return nil, false
+	}
+	token, err := s.previews.Verify(raw)
 	if err != nil {
-		s.logger.Error("failed to get base template", "error", err)
-		return nil, err
+		s.logger.Debug("rejected preview token", "ref", ref, "error", err)
+		return nil, false
+	}
+	page, err := s.assetManager.GetPreviewPage(ctx, ref)
+	if err != nil || page.Path != token.Path {
+		return nil, false
 	}
+	return page, true
+}
+
+// handlePreview handles GET /preview/{token} and renders the page the
+// token was minted for in the single template, which shows a preview
+// banner.
+func (s *Server) handlePreview(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Robots-Tag", "noindex, nofollow")
+	w.Header().Set("Cache-Control", "private, no-store")
 
-	// Load content template content
-	contentContent, err := s.assetManager.GetTemplateContent(s.config.Theme, name)
+	raw := chi.URLParam(r, "token")
+	if s.previews == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	token, err := s.previews.Verify(raw)
 	if err != nil {
-		s.logger.Error("failed to get content template", "name", name, "error", err)
-		return nil, err
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	page, err := s.assetManager.GetPreviewPage(ctx, token.Path)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
 	}
 
-	// Parse base template with custom functions
-	baseTmpl, err := template.New("base").Funcs(TemplateFuncs()).Parse(string(baseContent))
+	// Render with the page template of the visitor's theme so the
+	// preview looks like the published page.
+	html, err := s.renderTemplate(ctx, "single", map[string]any{
+		"Page":    page,
+		"Content": template.HTML(page.Content),
+		"Preview": &token,
+	})
 	if err != nil {
-		s.logger.Error("failed to parse base template", "error", err)
-		return nil, err
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Add("Vary", "Cookie")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(html)
+}
+
+// requireAdmin rejects requests without the configured admin bearer
+// token.
+func (s *Server) requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if s.config.AdminToken == "" ||
+			!ok ||
+			subtle.ConstantTimeCompare([]byte(auth), []byte(s.config.AdminToken)) != 1 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{
+				"error": "unauthorized",
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleCreatePreview handles POST /api/admin/previews
+// Expects form data with "path" and an optional "ttl" duration.
+func (s *Server) handleCreatePreview(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	var ttl time.Duration
+	if v := r.FormValue("ttl"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"error": "invalid ttl",
+			})
+			return
+		}
+		ttl = d
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	page, err := s.assetManager.GetPreviewPage(ctx, r.FormValue("path"))
+	if err != nil || s.previews == nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "page not found",
+		})
+		return
 	}
 
-	// Parse content template and associate with base
-	baseTmpl, err = baseTmpl.New("content").Parse(string(contentContent))
+	raw, token, err := s.previews.Mint(page.Path, ttl)
 	if err != nil {
-		s.logger.Error("failed to parse content template", "name", name, "error", err)
-		return nil, err
+		s.logger.Error("failed to mint preview token", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
 	}
 
-	var buf bytes.Buffer
-	err = baseTmpl.Execute(&buf, data)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"id":      token.ID,
+		"path":    token.Path,
+		"expires": token.Expires,
+		"url":     strings.TrimSuffix(s.config.BaseURL, "/") + "/preview/" + raw,
+	})
+}
+
+// handleRevokePreview handles DELETE /api/admin/previews/{id}
+func (s *Server) handleRevokePreview(w http.ResponseWriter, r *http.Request) {
+	if s.previews != nil {
+		if err := s.previews.Revoke(chi.URLParam(r, "id")); err != nil {
+			s.logger.Error("failed to revoke preview", "error", err)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"error": "failed to persist revocation",
+			})
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleReload handles POST /api/admin/reload requests, rereading the
+// content, syncing the search index with what changed and rescheduling
+// the next publication transition.
+func (s *Server) handleReload(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	if err := s.reload(ctx); err != nil {
+		s.logger.Error("failed to reload content", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "failed to reload content",
+		})
+		return
+	}
+
+	search, err := s.assetManager.Search(ctx)
 	if err != nil {
-		s.logger.Error("failed to execute template", "error", err)
-		return nil, err
+		s.logger.Error("failed to load search index", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "failed to load search index",
+		})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{
+		"documents": search.Len(),
+	})
+}
+
+// reload rereads the content and themes, syncs the search index with
+// what changed and reschedules the next publication transition.
+func (s *Server) reload(ctx context.Context) error {
+	s.assetManager.Reload()
+	if err := s.themes.Reload(); err != nil {
+		s.logger.Error("failed to reload themes", "error", err)
 	}
+	if err := s.assetManager.SyncSearch(ctx); err != nil {
+		return err
+	}
+	// Reloaded content may add or move a publication transition.
+	if err := s.scheduler.Start(ctx); err != nil {
+		s.logger.Error("failed to reschedule publication", "error", err)
+	}
+	return nil
+}
 
+// renderTemplate renders a page template of the request's theme within
+// its baseof layout.
+func (s *Server) renderTemplate(ctx context.Context, name string, data any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := s.themes.Render(&buf, s.theme(ctx), name, data); err != nil {
+		s.logger.Error("failed to execute template", "name", name, "error", err)
+		return nil, err
+	}
 	return buf.Bytes(), nil
 }
 
+// homePage is the data of the home page and of its experience and
+// skills partials.
+type homePage struct {
+	*Data
+	// SkillGroups are the skill profiles by category, including the
+	// technologies only listed on experience.
+	SkillGroups []SkillProfileCategory
+}
+
+func newHomePage(data *Data, now time.Time) homePage {
+	return homePage{Data: data, SkillGroups: NewSkillIndex(data, now).Categories()}
+}
+
 func (s *Server) handleGetHome(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
@@ -268,7 +1405,7 @@ func (s *Server) handleGetHome(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	html, err := s.renderTemplate("index", data)
+	html, err := s.renderTemplate(ctx, "index", newHomePage(data, time.Now()))
 	if err != nil {
 		s.logger.Error("failed to render index template", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -277,6 +1414,7 @@ func (s *Server) handleGetHome(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Add("Vary", "Cookie")
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.Header().Set("Cache-Control", "public, max-age=3600")
 	w.Write(html)
@@ -308,7 +1446,7 @@ func (s *Server) handleExperiencePartial(w http.ResponseWriter, r *http.Request)
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	_, err := s.assetManager.GetData(ctx)
+	data, err := s.assetManager.GetData(ctx)
 	if err != nil {
 		s.logger.Error("failed to get data for experience partial", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -317,10 +1455,18 @@ func (s *Server) handleExperiencePartial(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// TODO: Implement experience rendering
+	var buf bytes.Buffer
+	if err := s.themes.RenderPartial(&buf, s.theme(ctx), "experience-section", newHomePage(data, time.Now())); err != nil {
+		s.logger.Error("failed to render experience partial", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, "<p>Error loading experience</p>")
+		return
+	}
+	w.Header().Add("Vary", "Cookie")
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.Header().Set("Cache-Control", "public, max-age=3600")
-	fmt.Fprint(w, "<!-- Experience partial placeholder -->")
+	w.Write(buf.Bytes())
 }
 
 // handleSkillsPartial handles GET /api/skills/partial for htmx requests
@@ -328,7 +1474,7 @@ func (s *Server) handleSkillsPartial(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	_, err := s.assetManager.GetData(ctx)
+	data, err := s.assetManager.GetData(ctx)
 	if err != nil {
 		s.logger.Error("failed to get data for skills partial", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -337,14 +1483,40 @@ func (s *Server) handleSkillsPartial(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// TODO: Implement skills rendering
+	var buf bytes.Buffer
+	if err := s.themes.RenderPartial(&buf, s.theme(ctx), "skills-section", newHomePage(data, time.Now())); err != nil {
+		s.logger.Error("failed to render skills partial", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, "<p>Error loading skills</p>")
+		return
+	}
+	w.Header().Add("Vary", "Cookie")
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.Header().Set("Cache-Control", "public, max-age=3600")
-	fmt.Fprint(w, "<!-- Skills partial placeholder -->")
+	w.Write(buf.Bytes())
+}
+
+// resolveTheme stores the theme for the request in its context. See
+// ThemeManager.Resolve. Handlers that render with the theme add
+// "Vary: Cookie", since their responses vary by the theme cookie.
+func (s *Server) resolveTheme(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(WithTheme(r.Context(), s.themes.Resolve(r))))
+	})
+}
+
+// theme returns the theme resolved for a request, or the configured
+// theme outside of one.
+func (s *Server) theme(ctx context.Context) string {
+	if theme, ok := ThemeFromContext(ctx); ok {
+		return theme
+	}
+	return s.config.Theme
 }
 
 // handleThemeSwitch handles POST /api/theme for htmx theme switching
-// Expects form data with "theme" parameter
+// Expects form data with "theme" parameter naming an installed theme
 func (s *Server) handleThemeSwitch(w http.ResponseWriter, r *http.Request) {
 	theme := r.FormValue("theme")
 	if theme == "" {
@@ -353,15 +1525,23 @@ func (s *Server) handleThemeSwitch(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, "theme parameter required")
 		return
 	}
+	if !s.themes.IsValid(theme) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "unknown theme %q", theme)
+		return
+	}
 
-	// Set theme cookie for persistence
+	// Set theme cookie for persistence. It is only marked Secure when
+	// served over TLS, directly or behind a trusted TLS terminating
+	// proxy, so it still works over plain http in development.
 	http.SetCookie(w, &http.Cookie{
-		Name:     "theme",
+		Name:     ThemeCookie,
 		Value:    theme,
 		MaxAge:   365 * 24 * 60 * 60, // 1 year
 		Path:     "/",
 		HttpOnly: true,
-		Secure:   false, // Set to true in production
+		Secure:   s.isHTTPS(r),
 		SameSite: http.SameSiteLaxMode,
 	})
 
@@ -371,6 +1551,43 @@ func (s *Server) handleThemeSwitch(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, "<!-- Theme switched -->")
 }
 
+// isHTTPS reports whether r was made over TLS, directly or through a
+// trusted proxy that sets X-Forwarded-Proto.
+func (s *Server) isHTTPS(r *http.Request) bool {
+	if r.TLS != nil {
+		return true
+	}
+	if r.Header.Get("X-Forwarded-Proto") != "https" {
+		return false
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	for _, proxy := range s.proxies {
+		if proxy.Contains(addr.Unmap()) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseProxy parses a trusted proxy given as an address or CIDR range.
+func parseProxy(proxy string) (netip.Prefix, error) {
+	if strings.Contains(proxy, "/") {
+		return netip.ParsePrefix(proxy)
+	}
+	addr, err := netip.ParseAddr(proxy)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
 // handleExample handles GET /example and serves the example.html reference design
 func (s *Server) handleExample(w http.ResponseWriter, r *http.Request) {
 	exampleContent, err := fs.ReadFile(s.assetManager.Assets, "example.html")
@@ -397,9 +1614,13 @@ func (s *Server) handleStatic(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	filePath := filepath.Join("themes", s.config.Theme, "static", staticPath)
-
-	// Read file from embedded assets
+	// Read file from the theme, its parents or the default theme
+	filePath, ok := s.themes.StaticPath(s.theme(r.Context()), staticPath)
+	if !ok {
+		s.logger.Error("failed to find static file", "path", staticPath)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
 	content, err := fs.ReadFile(s.assetManager.Assets, filePath)
 	if err != nil {
 		s.logger.Error("failed to read static file", "path", filePath, "error", err)
@@ -407,13 +1628,30 @@ func (s *Server) handleStatic(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Set content type based on file extension
+	// Set content type based on file extension. The file depends on
+	// the theme, so it varies by the theme cookie.
 	contentType := getContentType(filePath)
+	w.Header().Add("Vary", "Cookie")
 	w.Header().Set("Content-Type", contentType)
 	w.Header().Set("Cache-Control", "public, max-age=86400") // Cache for 1 day
 	w.Write(content)
 }
 
+// handleHighlightCSS handles GET /static/css/highlight.css with the
+// stylesheet for highlighted code blocks.
+func (s *Server) handleHighlightCSS(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if err := HighlightCSS(&buf); err != nil {
+		s.logger.Error("failed to write highlight stylesheet", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/css; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=86400") // Cache for 1 day
+	w.Write(buf.Bytes())
+}
+
 // getContentType returns the appropriate Content-Type header for a file
 func getContentType(filePath string) string {
 	switch {
@@ -2,6 +2,9 @@ package portfolio
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -154,7 +157,7 @@ func TestHandleThemeSwitch(t *testing.T) {
 	server := NewServer(config, nil)
 
 	// Create a proper form-encoded POST request with body
-	body := "theme=dark"
+	body := "theme=green-nebula-terminal"
 	req := httptest.NewRequest("POST", "/api/theme", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	w := httptest.NewRecorder()
@@ -172,8 +175,9 @@ func TestHandleThemeSwitch(t *testing.T) {
 	for _, cookie := range cookies {
 		if cookie.Name == "theme" {
 			themeCookie = true
-			assert.Equal(t, "dark", cookie.Value)
+			assert.Equal(t, "green-nebula-terminal", cookie.Value)
 			assert.Equal(t, true, cookie.HttpOnly)
+			assert.False(t, cookie.Secure)
 		}
 	}
 	assert.True(t, themeCookie, "theme cookie should be set")
@@ -195,6 +199,118 @@ func TestHandleThemeSwitchMissingParam(t *testing.T) {
 	assert.Contains(t, string(body), "required")
 }
 
+func TestHandleThemeSwitchUnknownTheme(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), nil)
+
+	req := httptest.NewRequest("POST", "/api/theme", strings.NewReader("theme=dark"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	server.router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `unknown theme "dark"`)
+	assert.Empty(t, w.Result().Cookies())
+}
+
+func TestHandleThemeSwitchSecureCookie(t *testing.T) {
+	config := DefaultServerConfig()
+	config.TrustedProxies = []string{"10.0.0.0/8", "192.0.2.7", "bogus"}
+	server := NewServer(config, nil)
+
+	tests := []struct {
+		name      string
+		target    string
+		remote    string
+		forwarded string
+		secure    bool
+	}{
+		{"plain http", "/api/theme", "", "", false},
+		{"tls", "https://example.com/api/theme", "", "", true},
+		{"trusted proxy range", "/api/theme", "10.1.2.3:4567", "https", true},
+		{"trusted proxy address", "/api/theme", "192.0.2.7:4567", "https", true},
+		{"untrusted client", "/api/theme", "203.0.113.9:4567", "https", false},
+		{"trusted proxy over http", "/api/theme", "10.1.2.3:4567", "http", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.target, strings.NewReader("theme="+DefaultTheme))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.remote != "" {
+				req.RemoteAddr = tt.remote
+			}
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.forwarded)
+			}
+			w := httptest.NewRecorder()
+
+			server.router.ServeHTTP(w, req)
+
+			require.Len(t, w.Result().Cookies(), 1)
+			assert.Equal(t, tt.secure, w.Result().Cookies()[0].Secure)
+		})
+	}
+}
+
+func TestResolveTheme(t *testing.T) {
+	themes, _ := NewThemeManager(themeFixture(), "base", nil, template.FuncMap{"site": func() string { return "" }})
+	server := &Server{config: ServerConfig{Theme: "base"}, themes: themes}
+	handler := server.resolveTheme(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, server.theme(r.Context()))
+	}))
+
+	tests := []struct {
+		name   string
+		cookie string
+		query  string
+		want   string
+	}{
+		{"default", "", "", "base"},
+		{"cookie", "light", "", "light"},
+		{"query overrides cookie", "base", "light", "light"},
+		{"unknown cookie", "dark", "", "base"},
+		{"invalid theme cookie", "broken", "", "base"},
+		{"unknown query", "light", "dark", "light"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/?theme="+tt.query, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: ThemeCookie, Value: tt.cookie})
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Body.String())
+		})
+	}
+}
+
+func TestThemedResponsesVary(t *testing.T) {
+	config := DefaultServerConfig()
+	config.Theme = "green-nebula-terminal"
+	server := NewServer(config, nil)
+
+	tests := []struct {
+		target string
+		vary   string
+	}{
+		{"/archive", "Cookie"},
+		{"/static/css/stylesheet.css", "Cookie"},
+		{"/api/archive", ""},
+		{"/health", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, httptest.NewRequest("GET", tt.target, nil))
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.vary, w.Header().Get("Vary"))
+		})
+	}
+}
+
 func TestHandleExample(t *testing.T) {
 	config := DefaultServerConfig()
 	server := NewServer(config, nil)
@@ -274,6 +390,30 @@ func TestDefaultServerConfig(t *testing.T) {
 	assert.Equal(t, 1<<20, config.MaxHeaderBytes)
 }
 
+func TestHandleGetSite(t *testing.T) {
+	config := DefaultServerConfig()
+	config.BaseURL = ""
+	server := NewServer(config, nil)
+
+	req := httptest.NewRequest("GET", "/api/site", nil)
+	w := httptest.NewRecorder()
+
+	server.router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var site struct {
+		BaseURL string                 `json:"baseURL"`
+		Menus   map[string][]MenuEntry `json:"menus"`
+		Params  map[string]any         `json:"params"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&site))
+	assert.Equal(t, "https://jlrickert.me", site.BaseURL)
+	require.NotEmpty(t, site.Menus["main"])
+	assert.Equal(t, "About", site.Menus["main"][0].Name)
+	assert.Equal(t, "/about", site.Menus["main"][0].URL)
+	assert.Equal(t, "Jared Rickert", site.Params["author"])
+}
+
 func TestServerHandler(t *testing.T) {
 	config := DefaultServerConfig()
 	server := NewServer(config, nil)
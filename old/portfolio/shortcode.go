@@ -0,0 +1,632 @@
+package portfolio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrUnknownShortcode is returned for a shortcode without an
+// implementation.
+var ErrUnknownShortcode = errors.New("unknown shortcode")
+
+// Shortcode is a single Hugo shortcode call such as
+//
+//	{{< figure src="cover.png" alt="A cover" >}}
+//
+// Parameters are either positional (Args) or named (Params), never
+// both.
+type Shortcode struct {
+	Name   string
+	Args   []string
+	Params map[string]string
+	// Inner is the content between the opening and closing tags with any
+	// nested shortcodes expanded.
+	Inner template.HTML
+	// Page is the page the shortcode is called from.
+	Page *Page
+	// File is the path of the content file within the site, as in
+	// content/blog/post.md.
+	File string
+	// Line is the line of the call within the content file.
+	Line int
+}
+
+// Get returns a positional parameter for an int key and a named
+// parameter for a string key, as Hugo's .Get does. Missing parameters
+// are empty.
+func (s *Shortcode) Get(key any) string {
+	switch k := key.(type) {
+	case int:
+		if k >= 0 && k < len(s.Args) {
+			return s.Args[k]
+		}
+	case string:
+		return s.Params[k]
+	}
+	return ""
+}
+
+// IsNamedParams reports whether the call uses named parameters.
+func (s *Shortcode) IsNamedParams() bool {
+	return len(s.Params) > 0
+}
+
+// Position returns the file and line of the call, for error messages
+// in shortcode templates.
+func (s *Shortcode) Position() string {
+	if s.File == "" {
+		return strconv.Itoa(s.Line)
+	}
+	return fmt.Sprintf("%s:%d", s.File, s.Line)
+}
+
+// ShortcodeFunc renders a shortcode to HTML, or to markdown when called
+// with the {{% %}} delimiters.
+type ShortcodeFunc func(sc *Shortcode) (string, error)
+
+// Shortcodes maps shortcode names to their implementations.
+type Shortcodes map[string]ShortcodeFunc
+
+// BuiltinShortcodes returns the shortcodes implemented in Go: figure,
+// ref, relref, highlight, youtube and gist.
+func BuiltinShortcodes() Shortcodes {
+	return Shortcodes{
+		"figure":    figureShortcode,
+		"ref":       refShortcode,
+		"relref":    refShortcode,
+		"highlight": highlightShortcode,
+		"youtube":   youtubeShortcode,
+		"gist":      gistShortcode,
+	}
+}
+
+// LoadShortcodes returns the built-in shortcodes together with the Go
+// templates in dir, named after their path without the .html
+// extension, so layouts/_shortcodes/note.html is called as {{< note >}}.
+// A template replaces a built-in of the same name. A missing dir only
+// gives the built-ins.
+//
+// Templates are executed with the Shortcode as data and have the
+// TemplateFuncs along with markdownify and safeHTML.
+func LoadShortcodes(fsys fs.FS, dir string) (Shortcodes, error) {
+	shortcodes := BuiltinShortcodes()
+	err := fs.WalkDir(fsys, dir, func(fp string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if fp == dir && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() || path.Ext(fp) != ".html" {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, fp)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(fp, dir+"/"), ".html")
+		tmpl, err := template.New(name).
+			Funcs(TemplateFuncs()).
+			Funcs(shortcodeTemplateFuncs).
+			Parse(string(data))
+		if err != nil {
+			return fmt.Errorf("failed to parse shortcode %s: %w", fp, err)
+		}
+		shortcodes[name] = templateShortcode(tmpl)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shortcodes, nil
+}
+
+var shortcodeTemplateFuncs = template.FuncMap{
+	"markdownify": markdownify,
+	"safeHTML": func(s string) template.HTML {
+		return template.HTML(s)
+	},
+}
+
+// templateShortcode adapts a shortcode template to a ShortcodeFunc.
+func templateShortcode(tmpl *template.Template) ShortcodeFunc {
+	return func(sc *Shortcode) (string, error) {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, sc); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+}
+
+// markdownify renders inline markdown, dropping the paragraph around
+// a single line as Hugo does.
+func markdownify(s any) (template.HTML, error) {
+	var buf bytes.Buffer
+	if err := Markdown.Convert([]byte(fmt.Sprint(s)), &buf); err != nil {
+		return "", err
+	}
+	out := strings.TrimSuffix(buf.String(), "\n")
+	if inner, ok := strings.CutPrefix(out, "<p>"); ok {
+		if inner, ok := strings.CutSuffix(inner, "</p>"); ok && !strings.Contains(inner, "<p>") {
+			out = inner
+		}
+	}
+	return template.HTML(out), nil
+}
+
+// ShortcodeError is a shortcode that could not be parsed or expanded.
+type ShortcodeError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e *ShortcodeError) Error() string {
+	return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+}
+
+func (e *ShortcodeError) Unwrap() error {
+	return e.Err
+}
+
+// shortcodeTag is an opening or closing shortcode tag in the source.
+type shortcodeTag struct {
+	start, end  int
+	markdown    bool
+	closing     bool
+	selfClosing bool
+	name        string
+	args        []string
+	params      map[string]string
+}
+
+// shortcodeItem is either literal text or a tag.
+type shortcodeItem struct {
+	text string
+	tag  *shortcodeTag
+}
+
+// shortcodeSyntaxError is a parse error at a byte offset.
+type shortcodeSyntaxError struct {
+	pos int
+	msg string
+}
+
+func (e *shortcodeSyntaxError) Error() string {
+	return e.msg
+}
+
+// lexShortcodes splits src into text and shortcode tags. Commented
+// calls such as {{</* figure */>}} are kept as literal text without the
+// comment markers, so shortcodes can be documented.
+func lexShortcodes(src string) ([]shortcodeItem, error) {
+	var items []shortcodeItem
+	text := func(s string) {
+		if s == "" {
+			return
+		}
+		if n := len(items); n > 0 && items[n-1].tag == nil {
+			items[n-1].text += s
+			return
+		}
+		items = append(items, shortcodeItem{text: s})
+	}
+
+	pos := 0
+	for {
+		next := nextShortcode(src, pos)
+		if next < 0 {
+			text(src[pos:])
+			return items, nil
+		}
+		text(src[pos:next])
+
+		open, close := "{{<", ">}}"
+		if src[next+2] == '%' {
+			open, close = "{{%", "%}}"
+		}
+		body := next + len(open)
+		if rest := strings.TrimLeft(src[body:], " \t"); strings.HasPrefix(rest, "/*") {
+			start := len(src) - len(rest) + 2
+			end := strings.Index(src[start:], "*/")
+			if end < 0 {
+				return nil, &shortcodeSyntaxError{next, "unclosed shortcode comment"}
+			}
+			end += start
+			after := strings.TrimLeft(src[end+2:], " \t")
+			if !strings.HasPrefix(after, close) {
+				return nil, &shortcodeSyntaxError{next, "unclosed shortcode comment"}
+			}
+			text(open + src[start:end] + close)
+			pos = len(src) - len(after) + len(close)
+			continue
+		}
+
+		tag, err := parseShortcodeTag(src, next, open, close)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, shortcodeItem{tag: tag})
+		pos = tag.end
+	}
+}
+
+// nextShortcode returns the offset of the next {{< or {{% from pos.
+func nextShortcode(src string, pos int) int {
+	for {
+		i := strings.Index(src[pos:], "{{")
+		if i < 0 || pos+i+2 >= len(src) {
+			return -1
+		}
+		i += pos
+		if src[i+2] == '<' || src[i+2] == '%' {
+			return i
+		}
+		pos = i + 2
+	}
+}
+
+// parseShortcodeTag parses the tag starting at start.
+func parseShortcodeTag(src string, start int, open, close string) (*shortcodeTag, error) {
+	tag := &shortcodeTag{start: start, markdown: open == "{{%"}
+	pos := start + len(open)
+	unclosed := &shortcodeSyntaxError{start, "unclosed shortcode"}
+
+	skipSpace := func() {
+		for pos < len(src) && (src[pos] == ' ' || src[pos] == '\t' || src[pos] == '\n' || src[pos] == '\r') {
+			pos++
+		}
+	}
+	word := func() string {
+		begin := pos
+		for pos < len(src) && !unicode.IsSpace(rune(src[pos])) && src[pos] != '=' &&
+			!strings.HasPrefix(src[pos:], close) && !strings.HasPrefix(src[pos:], "/"+close) {
+			pos++
+		}
+		return src[begin:pos]
+	}
+	value := func() (string, error) {
+		if pos >= len(src) {
+			return "", unclosed
+		}
+		switch src[pos] {
+		case '"':
+			var b strings.Builder
+			for pos++; pos < len(src); pos++ {
+				switch src[pos] {
+				case '\\':
+					if pos+1 < len(src) {
+						pos++
+						b.WriteByte(src[pos])
+					}
+				case '"':
+					pos++
+					return b.String(), nil
+				default:
+					b.WriteByte(src[pos])
+				}
+			}
+			return "", &shortcodeSyntaxError{start, "unterminated quoted parameter"}
+		case '`':
+			end := strings.IndexByte(src[pos+1:], '`')
+			if end < 0 {
+				return "", &shortcodeSyntaxError{start, "unterminated quoted parameter"}
+			}
+			v := src[pos+1 : pos+1+end]
+			pos += end + 2
+			return v, nil
+		}
+		return word(), nil
+	}
+
+	skipSpace()
+	if strings.HasPrefix(src[pos:], "/") {
+		tag.closing = true
+		pos++
+		skipSpace()
+	}
+	tag.name = word()
+	if tag.name == "" {
+		return nil, &shortcodeSyntaxError{start, "missing shortcode name"}
+	}
+
+	for {
+		skipSpace()
+		if pos >= len(src) {
+			return nil, unclosed
+		}
+		if strings.HasPrefix(src[pos:], close) {
+			tag.end = pos + len(close)
+			return tag, nil
+		}
+		if strings.HasPrefix(src[pos:], "/"+close) && !tag.closing {
+			tag.selfClosing = true
+			tag.end = pos + 1 + len(close)
+			return tag, nil
+		}
+		if tag.closing {
+			return nil, &shortcodeSyntaxError{start, fmt.Sprintf("closing tag of %q has parameters", tag.name)}
+		}
+
+		quoted := src[pos] == '"' || src[pos] == '`'
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		if !quoted && pos < len(src) && src[pos] == '=' {
+			pos++
+			named, err := value()
+			if err != nil {
+				return nil, err
+			}
+			if tag.params == nil {
+				tag.params = make(map[string]string)
+			}
+			tag.params[v] = named
+		} else {
+			tag.args = append(tag.args, v)
+		}
+		if len(tag.args) > 0 && len(tag.params) > 0 {
+			return nil, &shortcodeSyntaxError{start, fmt.Sprintf("%q mixes named and positional parameters", tag.name)}
+		}
+	}
+}
+
+// shortcodeNode is an item with the items between its opening and
+// closing tags.
+type shortcodeNode struct {
+	shortcodeItem
+	inner    string
+	children []shortcodeNode
+}
+
+// nestShortcodes pairs opening and closing tags. A tag without a
+// matching closing tag has no inner content.
+func nestShortcodes(src string, items []shortcodeItem) ([]shortcodeNode, error) {
+	var nodes []shortcodeNode
+	for i := 0; i < len(items); i++ {
+		item := items[i]
+		if item.tag == nil {
+			nodes = append(nodes, shortcodeNode{shortcodeItem: item})
+			continue
+		}
+		if item.tag.closing {
+			return nil, &shortcodeSyntaxError{item.tag.start, fmt.Sprintf("closing tag of %q without an opening tag", item.tag.name)}
+		}
+		node := shortcodeNode{shortcodeItem: item}
+		if !item.tag.selfClosing {
+			if end := closingShortcode(items, i); end > 0 {
+				children, err := nestShortcodes(src, items[i+1:end])
+				if err != nil {
+					return nil, err
+				}
+				node.children = children
+				node.inner = src[item.tag.end:items[end].tag.start]
+				i = end
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// closingShortcode returns the index of the tag closing items[open],
+// or -1.
+func closingShortcode(items []shortcodeItem, open int) int {
+	name := items[open].tag.name
+	depth := 0
+	for i := open + 1; i < len(items); i++ {
+		tag := items[i].tag
+		if tag == nil || tag.name != name {
+			continue
+		}
+		switch {
+		case tag.closing && depth == 0:
+			return i
+		case tag.closing:
+			depth--
+		case !tag.selfClosing:
+			depth++
+		}
+	}
+	return -1
+}
+
+// shortcodeExpander expands the shortcodes of one page.
+type shortcodeExpander struct {
+	page *Page
+	// file is the path of the page within the site, for errors.
+	file       string
+	shortcodes Shortcodes
+	src        string
+	// lineOffset is the number of lines before src in the file.
+	lineOffset int
+	// placeholders holds the HTML output of {{< >}} calls, which is
+	// substituted after markdown rendering.
+	placeholders map[string]string
+}
+
+// expandShortcodes replaces the shortcodes in content. The output of
+// {{% %}} calls is inserted as markdown. When placeholders is true the
+// output of {{< >}} calls is replaced by placeholders, to be restored
+// with restoreShortcodes once the markdown is rendered; otherwise it is
+// inserted as is.
+func expandShortcodes(page *Page, file string, shortcodes Shortcodes, content []byte, lineOffset int, placeholders bool) ([]byte, map[string]string, error) {
+	if !bytes.Contains(content, []byte("{{<")) && !bytes.Contains(content, []byte("{{%")) {
+		return content, nil, nil
+	}
+	e := &shortcodeExpander{
+		page:       page,
+		file:       file,
+		shortcodes: shortcodes,
+		src:        string(content),
+		lineOffset: lineOffset,
+	}
+	if placeholders {
+		e.placeholders = make(map[string]string)
+	}
+
+	items, err := lexShortcodes(e.src)
+	if err == nil {
+		var nodes []shortcodeNode
+		nodes, err = nestShortcodes(e.src, items)
+		if err == nil {
+			var out string
+			out, err = e.expand(nodes, placeholders)
+			if err == nil {
+				return []byte(out), e.placeholders, nil
+			}
+		}
+	}
+	var syntaxErr *shortcodeSyntaxError
+	if errors.As(err, &syntaxErr) {
+		return nil, nil, &ShortcodeError{File: file, Line: e.line(syntaxErr.pos), Err: syntaxErr}
+	}
+	return nil, nil, err
+}
+
+func (e *shortcodeExpander) line(pos int) int {
+	return e.lineOffset + strings.Count(e.src[:pos], "\n") + 1
+}
+
+func (e *shortcodeExpander) expand(nodes []shortcodeNode, top bool) (string, error) {
+	var b strings.Builder
+	for _, node := range nodes {
+		if node.tag == nil {
+			b.WriteString(node.text)
+			continue
+		}
+		tag := node.tag
+		line := e.line(tag.start)
+		fn, ok := e.shortcodes[tag.name]
+		if !ok {
+			return "", &ShortcodeError{
+				File: e.file,
+				Line: line,
+				Err:  fmt.Errorf("%w %q", ErrUnknownShortcode, tag.name),
+			}
+		}
+
+		inner := node.inner
+		if len(node.children) > 0 {
+			var err error
+			if inner, err = e.expand(node.children, false); err != nil {
+				return "", err
+			}
+		}
+		out, err := fn(&Shortcode{
+			Name:   tag.name,
+			Args:   tag.args,
+			Params: tag.params,
+			Inner:  template.HTML(inner),
+			Page:   e.page,
+			File:   e.file,
+			Line:   line,
+		})
+		if err != nil {
+			return "", &ShortcodeError{
+				File: e.file,
+				Line: line,
+				Err:  fmt.Errorf("shortcode %q: %w", tag.name, err),
+			}
+		}
+
+		if tag.markdown || !top || e.placeholders == nil {
+			b.WriteString(out)
+			continue
+		}
+		key := fmt.Sprintf("SHORTCODEHTML%dX", len(e.placeholders))
+		e.placeholders[key] = out
+		b.WriteString(key)
+	}
+	return b.String(), nil
+}
+
+// restoreShortcodes puts the shortcode output back in place of its
+// placeholders. A placeholder alone in a paragraph replaces the
+// paragraph, so block output is not wrapped in <p>.
+func restoreShortcodes(rendered []byte, placeholders map[string]string) []byte {
+	if len(placeholders) == 0 {
+		return rendered
+	}
+	out := string(rendered)
+	for key, html := range placeholders {
+		out = strings.ReplaceAll(out, "<p>"+key+"</p>", html)
+		out = strings.ReplaceAll(out, key, html)
+	}
+	return []byte(out)
+}
+
+// pageRef is a ref or relref shortcode call, resolved once the whole
+// content tree is indexed.
+type pageRef struct {
+	path   string
+	anchor string
+	line   int
+	target *Page
+}
+
+// refMarker returns the placeholder of the i-th ref of a page.
+func refMarker(i int) string {
+	return fmt.Sprintf("SHORTCODEREF%dX", i)
+}
+
+// resolveRefs links the ref and relref calls of every page to their
+// target, reporting the first one that does not resolve.
+func (idx *ContentIndex) resolveRefs(root string) error {
+	for _, page := range idx.pages {
+		for _, ref := range page.refs {
+			ref.target = page
+			if ref.path != "" {
+				ref.target = idx.lookupRef(page, ref.path)
+			}
+			if ref.target == nil {
+				return &ShortcodeError{
+					File: path.Join(root, page.Path),
+					Line: ref.line,
+					Err:  fmt.Errorf("ref %q not found", ref.path),
+				}
+			}
+		}
+	}
+	idx.renderRefs()
+	return nil
+}
+
+// lookupRef resolves a ref target relative to the calling page first,
+// then anywhere as Lookup does.
+func (idx *ContentIndex) lookupRef(from *Page, ref string) *Page {
+	if !strings.HasPrefix(ref, "/") {
+		if page := idx.ByPath(path.Join(path.Dir(from.Path), ref)); page != nil {
+			return page
+		}
+	}
+	return idx.Lookup(ref)
+}
+
+// renderRefs writes the URLs of resolved refs into page content. It is
+// run again when the permalinks change.
+func (idx *ContentIndex) renderRefs() {
+	for _, page := range idx.pages {
+		if len(page.refs) == 0 {
+			continue
+		}
+		content := string(page.unresolved)
+		for i, ref := range page.refs {
+			url := ref.target.URL()
+			if ref.anchor != "" {
+				url += "#" + ref.anchor
+			}
+			content = strings.ReplaceAll(content, refMarker(i), url)
+		}
+		page.Content = []byte(content)
+	}
+}
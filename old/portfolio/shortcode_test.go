@@ -0,0 +1,186 @@
+package portfolio
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShortcodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "figure block",
+			src:      joinLines("Before", "", `{{< figure src="cover.png" alt="Cover" caption="The *new* cover" link="/about/" >}}`, "", "After"),
+			contains: []string{`<p>Before</p>` + "\n" + `<figure><a href="/about/"><img src="cover.png" alt="Cover"></a><figcaption><p>The <em>new</em> cover</p></figcaption></figure>` + "\n" + `<p>After</p>`},
+		},
+		{
+			name:     "inline",
+			src:      `Watch {{< gist jlrickert abc123 main.go >}} now.` + "\n",
+			contains: []string{`<p>Watch <script src="https://gist.github.com/jlrickert/abc123.js?file=main.go"></script> now.</p>`},
+		},
+		{
+			name:     "youtube",
+			src:      `{{< youtube id="dQw4w9WgXcQ" title="A talk" start="30" >}}` + "\n",
+			contains: []string{`src="https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ?start=30"`, `title="A talk"`, `loading="lazy"`},
+			excludes: []string{"<p>"},
+		},
+		{
+			name:     "highlight",
+			src:      joinLines(`{{< highlight go "linenos=table,hl_lines=2" >}}`, "x := 1", "y := 2", "{{< /highlight >}}"),
+			contains: []string{`<div class="highlight">`, `class="lntable"`, `<span class="line hl">`},
+		},
+		{
+			name:     "comment",
+			src:      `Write {{</* figure src="x.png" */>}} for a figure.` + "\n",
+			contains: []string{`<p>Write {{&lt; figure src=&quot;x.png&quot; &gt;}} for a figure.</p>`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := testPage("blog/post.md", "---\ntitle: Post\n---\n"+tt.src)
+			for _, want := range tt.contains {
+				assert.Contains(t, string(page.Content), want)
+			}
+			for _, unwanted := range tt.excludes {
+				assert.NotContains(t, string(page.Content), unwanted)
+			}
+		})
+	}
+}
+
+func TestShortcodeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"unknown", "Intro\n\n{{< tweet 123 >}}\n", `content/blog/post.md:6: unknown shortcode "tweet"`},
+		{"unclosed", "{{< figure src=\"a.png\"\n", "content/blog/post.md:4: unclosed shortcode"},
+		{"stray closing tag", "x\n{{< /figure >}}\n", `content/blog/post.md:5: closing tag of "figure" without an opening tag`},
+		{"mixed parameters", `{{< youtube abc id="x" >}}`, `content/blog/post.md:4: "youtube" mixes named and positional parameters`},
+		{"invalid video", `{{< youtube "not a video" >}}`, `content/blog/post.md:4: shortcode "youtube": invalid video id "not a video"`},
+		{"missing ref", "See [it]({{< ref \"nowhere\" >}}).\n", `content/blog/post.md:4: ref "nowhere" not found`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewContentIndex(context.Background(), fstest.MapFS{
+				"content/blog/post.md": {Data: []byte("---\ntitle: Post\n---\n" + tt.src)},
+			}, "content")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+			var scErr *ShortcodeError
+			require.True(t, errors.As(err, &scErr))
+			assert.Equal(t, "content/blog/post.md", scErr.File)
+		})
+	}
+
+	// Pages loaded on their own name the file the same way.
+	_, err := loadPage("content", "blog/post.md", []byte("---\ntitle: Post\n---\n{{< tweet >}}"), BuiltinShortcodes())
+	var scErr *ShortcodeError
+	require.True(t, errors.As(err, &scErr))
+	assert.Equal(t, "content/blog/post.md", scErr.File)
+
+	_, err = NewContentIndex(context.Background(), fstest.MapFS{
+		"content/post.md": {Data: []byte("---\ntitle: Post\n---\n{{< tweet >}}")},
+	}, "content")
+	assert.ErrorIs(t, err, ErrUnknownShortcode)
+}
+
+func TestHighlightShortcodeLineNumbers(t *testing.T) {
+	// hl_lines counts from the first line of the snippet, not from
+	// linenostart, whichever option comes first.
+	for _, options := range []string{
+		"linenos=true,linenostart=10,hl_lines=1",
+		"hl_lines=1,linenos=true,linenostart=10",
+	} {
+		out, err := highlightShortcode(&Shortcode{
+			Name:  "highlight",
+			Args:  []string{"go", options},
+			Inner: "x := 1\ny := 2\n",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(out, `class="line hl"`), options)
+		assert.Contains(t, out, ">10</span>", options)
+	}
+}
+
+func TestHighlightShortcodeIgnoresUnsupportedOptions(t *testing.T) {
+	out, err := highlightShortcode(&Shortcode{
+		Name:  "highlight",
+		Args:  []string{"go", "style=monokai,anchorlinenos=true,lineanchors=x,hl_lines=1"},
+		Inner: "x := 1\n",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, out, `class="line hl"`)
+
+	_, err = highlightShortcode(&Shortcode{Name: "highlight", Args: []string{"go", "linenos=maybe"}})
+	assert.ErrorContains(t, err, `invalid linenos "maybe"`)
+}
+
+func TestShortcodeRefs(t *testing.T) {
+	idx, err := NewContentIndex(context.Background(), fstest.MapFS{
+		"content/blog/first.md": {Data: []byte("---\ntitle: First\nslug: hello\n---\n## Setup\n")},
+		"content/blog/second.md": {Data: []byte(joinLines(
+			"---", "title: Second", "---",
+			`[first]({{< ref "first#setup" >}}), [about]({{< relref "/about" >}}) and [here]({{< ref "#top" >}})`,
+		))},
+		"content/about.md": {Data: []byte("---\ntitle: About\n---\n")},
+	}, "content")
+	require.NoError(t, err)
+
+	second := idx.ByPath("blog/second")
+	require.NotNil(t, second)
+	assert.Contains(t, string(second.Content), `<a href="/blog/hello/#setup">first</a>, <a href="/about/">about</a> and <a href="/blog/second/#top">here</a>`)
+
+	idx.SetPermalinks(Permalinks{Page: map[string]string{"blog": "/posts/:slug/"}})
+	assert.Contains(t, string(second.Content), `<a href="/posts/hello/#setup">first</a>`)
+}
+
+func TestLoadShortcodes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"themes/t/layouts/_shortcodes/note.html":        {Data: []byte(`<aside class="note">{{ .Get 0 }}: {{ .Inner | markdownify }}</aside>`)},
+		"themes/t/layouts/_shortcodes/figure.html":      {Data: []byte(`<img src="{{ .Get "src" }}">`)},
+		"themes/t/layouts/_shortcodes/strong.html":      {Data: []byte(`**{{ .Get 0 }}**`)},
+		"themes/t/layouts/_shortcodes/site/credit.html": {Data: []byte(`<p>{{ .Page.Title }} at {{ .Position }}</p>`)},
+	}
+	shortcodes, err := LoadShortcodes(fsys, "themes/t/layouts/_shortcodes")
+	require.NoError(t, err)
+	assert.Contains(t, shortcodes, "youtube")
+
+	page, err := loadPage("content", "blog/post.md", []byte(joinLines(
+		"---", "title: Post", "---",
+		`{{< note "Tip" >}}Use **go vet**{{< /note >}}`,
+		"",
+		`{{< figure src="a.png" caption="ignored" >}}`,
+		"",
+		`{{< site/credit />}}`,
+		"",
+		`{{% strong rendered %}} and {{< strong raw >}}`,
+	)), shortcodes)
+	require.NoError(t, err)
+	assert.Contains(t, string(page.Content), `<aside class="note">Tip: Use <strong>go vet</strong></aside>`)
+	assert.Contains(t, string(page.Content), `<img src="a.png">`)
+	assert.Contains(t, string(page.Content), `<p>Post at content/blog/post.md:8</p>`)
+	assert.Contains(t, string(page.Content), `<p><strong>rendered</strong> and **raw**</p>`)
+
+	shortcodes, err = LoadShortcodes(fsys, "themes/missing/layouts/_shortcodes")
+	require.NoError(t, err)
+	assert.Len(t, shortcodes, len(BuiltinShortcodes()))
+
+	_, err = LoadShortcodes(fstest.MapFS{
+		"_shortcodes/bad.html": {Data: []byte(`{{ .Get `)},
+	}, "_shortcodes")
+	assert.ErrorContains(t, err, "failed to parse shortcode _shortcodes/bad.html")
+}
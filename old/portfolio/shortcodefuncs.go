@@ -0,0 +1,260 @@
+package portfolio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+)
+
+// figureShortcode writes the same markup as Hugo's embedded figure
+// shortcode:
+//
+//	{{< figure src="cover.png" alt="Cover" caption="The *new* cover" link="/about/" >}}
+func figureShortcode(sc *Shortcode) (string, error) {
+	src := sc.Get("src")
+	if src == "" {
+		return "", errors.New("missing src")
+	}
+	attr := func(b *strings.Builder, name, value string) {
+		if value != "" {
+			fmt.Fprintf(b, ` %s="%s"`, name, html.EscapeString(value))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("<figure")
+	attr(&b, "class", sc.Get("class"))
+	b.WriteString(">")
+	if link := sc.Get("link"); link != "" {
+		b.WriteString("<a")
+		attr(&b, "href", link)
+		attr(&b, "target", sc.Get("target"))
+		attr(&b, "rel", sc.Get("rel"))
+		b.WriteString(">")
+	}
+	b.WriteString("<img")
+	attr(&b, "src", src)
+	alt := sc.Get("alt")
+	if alt == "" {
+		alt = sc.Get("caption")
+	}
+	attr(&b, "alt", alt)
+	attr(&b, "width", sc.Get("width"))
+	attr(&b, "height", sc.Get("height"))
+	attr(&b, "loading", sc.Get("loading"))
+	b.WriteString(">")
+	if sc.Get("link") != "" {
+		b.WriteString("</a>")
+	}
+
+	title, caption, credit := sc.Get("title"), sc.Get("caption"), sc.Get("attr")
+	if title != "" || caption != "" || credit != "" {
+		b.WriteString("<figcaption>")
+		if title != "" {
+			fmt.Fprintf(&b, "<h4>%s</h4>", html.EscapeString(title))
+		}
+		if caption != "" || credit != "" {
+			b.WriteString("<p>")
+			md, err := markdownify(caption)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(string(md))
+			if credit != "" {
+				md, err := markdownify(credit)
+				if err != nil {
+					return "", err
+				}
+				if link := sc.Get("attrlink"); link != "" {
+					fmt.Fprintf(&b, `<a href="%s">%s</a>`, html.EscapeString(link), md)
+				} else {
+					b.WriteString(string(md))
+				}
+			}
+			b.WriteString("</p>")
+		}
+		b.WriteString("</figcaption>")
+	}
+	b.WriteString("</figure>")
+	return b.String(), nil
+}
+
+// refShortcode links to another page by path, slug or name, with an
+// optional #anchor. The URL is written once the content tree is
+// indexed, so a missing page fails the index. ref and relref both give
+// the site relative URL since the site is served from a single origin.
+//
+//	[setup]({{< ref "blog/first-post#setup" >}})
+func refShortcode(sc *Shortcode) (string, error) {
+	target := sc.Get(0)
+	if target == "" {
+		target = sc.Get("path")
+	}
+	if target == "" {
+		return "", errors.New("missing page path")
+	}
+	ref := &pageRef{line: sc.Line}
+	ref.path, ref.anchor, _ = strings.Cut(target, "#")
+	sc.Page.refs = append(sc.Page.refs, ref)
+	return refMarker(len(sc.Page.refs) - 1), nil
+}
+
+// highlightShortcode highlights the inner content like a fenced code
+// block, with Hugo's options:
+//
+//	{{< highlight go "linenos=table,hl_lines=2 4-6,linenostart=10" >}}
+//
+// Other options, such as style or lineanchors, are ignored so content
+// written for Hugo still renders, always in HighlightStyle.
+func highlightShortcode(sc *Shortcode) (string, error) {
+	lexer := lexers.Get(sc.Get(0))
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	lexer = chroma.Coalesce(lexer)
+
+	opts := slices.Clone(highlightFormatOptions)
+	var highlight [][2]int
+	start := 1
+	for option := range strings.SplitSeq(sc.Get(1), ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		value = strings.Trim(value, `"'`)
+		switch key {
+		case "linenos":
+			switch value {
+			case "table":
+				opts = append(opts, chromahtml.WithLineNumbers(true), chromahtml.LineNumbersInTable(true))
+			case "true", "inline":
+				opts = append(opts, chromahtml.WithLineNumbers(true))
+			case "false":
+			default:
+				return "", fmt.Errorf("invalid linenos %q", value)
+			}
+		case "hl_lines":
+			lines, err := parseLineRanges(value)
+			if err != nil {
+				return "", err
+			}
+			highlight = lines
+		case "linenostart":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return "", fmt.Errorf("invalid linenostart %q", value)
+			}
+			start = n
+		}
+	}
+	// As in Hugo, hl_lines counts from the first line of the snippet
+	// whatever linenostart is, while chroma counts from linenostart.
+	for i := range highlight {
+		highlight[i][0] += start - 1
+		highlight[i][1] += start - 1
+	}
+	opts = append(opts, chromahtml.BaseLineNumber(start), chromahtml.HighlightLines(highlight))
+
+	iterator, err := lexer.Tokenise(nil, strings.Trim(string(sc.Inner), "\n"))
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(`<div class="highlight">`)
+	if err := chromahtml.New(opts...).Format(&buf, HighlightStyle, iterator); err != nil {
+		return "", err
+	}
+	buf.WriteString("</div>")
+	return buf.String(), nil
+}
+
+// parseLineRanges parses line numbers and ranges such as "2 4-6".
+func parseLineRanges(s string) ([][2]int, error) {
+	var ranges [][2]int
+	for _, field := range strings.Fields(s) {
+		from, to, isRange := strings.Cut(field, "-")
+		start, err := strconv.Atoi(from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hl_lines %q", s)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(to); err != nil {
+				return nil, fmt.Errorf("invalid hl_lines %q", s)
+			}
+		}
+		ranges = append(ranges, [2]int{start, end})
+	}
+	return ranges, nil
+}
+
+var youtubeID = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// youtubeShortcode embeds a video from youtube-nocookie.com, which sets
+// no cookies until the video is played. The iframe loads lazily.
+//
+//	{{< youtube id="dQw4w9WgXcQ" title="A talk" start="30" >}}
+func youtubeShortcode(sc *Shortcode) (string, error) {
+	id := sc.Get(0)
+	if id == "" {
+		id = sc.Get("id")
+	}
+	if !youtubeID.MatchString(id) {
+		return "", fmt.Errorf("invalid video id %q", id)
+	}
+
+	query := url.Values{}
+	if start := sc.Get("start"); start != "" {
+		if _, err := strconv.Atoi(start); err != nil {
+			return "", fmt.Errorf("invalid start %q", start)
+		}
+		query.Set("start", start)
+	}
+	if sc.Get("autoplay") == "true" {
+		query.Set("autoplay", "1")
+	}
+	src := "https://www.youtube-nocookie.com/embed/" + id
+	if len(query) > 0 {
+		src += "?" + query.Encode()
+	}
+	title := sc.Get("title")
+	if title == "" {
+		title = "YouTube video"
+	}
+
+	return fmt.Sprintf(`<div style="position: relative; padding-bottom: 56.25%%; height: 0; overflow: hidden;">`+
+		`<iframe src="%s" title="%s" style="position: absolute; top: 0; left: 0; width: 100%%; height: 100%%; border: 0;" `+
+		`allow="accelerometer; autoplay; clipboard-write; encrypted-media; gyroscope; picture-in-picture; web-share" `+
+		`referrerpolicy="strict-origin-when-cross-origin" loading="lazy" allowfullscreen></iframe></div>`,
+		html.EscapeString(src), html.EscapeString(title)), nil
+}
+
+var (
+	gistUser = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+	gistID   = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+)
+
+// gistShortcode embeds a GitHub gist, optionally a single file of it.
+//
+//	{{< gist jlrickert 4e2b1f0c main.go >}}
+func gistShortcode(sc *Shortcode) (string, error) {
+	user, id, file := sc.Get(0), sc.Get(1), sc.Get(2)
+	if !gistUser.MatchString(user) {
+		return "", fmt.Errorf("invalid gist user %q", user)
+	}
+	if !gistID.MatchString(id) {
+		return "", fmt.Errorf("invalid gist id %q", id)
+	}
+	src := fmt.Sprintf("https://gist.github.com/%s/%s.js", user, id)
+	if file != "" {
+		src += "?file=" + url.QueryEscape(file)
+	}
+	return fmt.Sprintf(`<script src="%s"></script>`, html.EscapeString(src)), nil
+}
@@ -0,0 +1,229 @@
+package portfolio
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SiteConfig is the Go view of Hugo's hugo.yaml, merged with the config
+// of the active theme.
+type SiteConfig struct {
+	BaseURL      string
+	LanguageCode string
+	Title        string
+	Theme        string
+
+	Permalinks Permalinks
+	Menus      map[string][]MenuEntry
+	Params     map[string]any
+	// Taxonomies maps singular taxonomy names to plural ones. Hugo's
+	// defaults apply when the config has none.
+	Taxonomies map[string]string
+
+	// Raw is the merged configuration as decoded from YAML.
+	Raw map[string]any
+}
+
+// MenuEntry is a single entry of a Hugo menu.
+type MenuEntry struct {
+	Name       string         `yaml:"name" json:"name"`
+	Identifier string         `yaml:"identifier" json:"identifier,omitempty"`
+	Parent     string         `yaml:"parent" json:"parent,omitempty"`
+	PageRef    string         `yaml:"pageRef" json:"pageRef,omitempty"`
+	URL        string         `yaml:"url" json:"url"`
+	Weight     int            `yaml:"weight" json:"weight"`
+	Params     map[string]any `yaml:"params" json:"params,omitempty"`
+}
+
+// themeMerge lists the root keys a theme config may contribute, with
+// Hugo's default merge strategy for each. "deep" merges nested maps and
+// "shallow" only adds missing top level entries. Every other key is
+// owned by the project config.
+var themeMerge = map[string]string{
+	"params":     "deep",
+	"markup":     "deep",
+	"menus":      "shallow",
+	"permalinks": "shallow",
+	"outputs":    "shallow",
+	"taxonomies": "shallow",
+}
+
+// LoadSiteConfig reads the Hugo config at name within fsys and merges in
+// themes/<theme>/hugo.yaml when the theme has one.
+func LoadSiteConfig(fsys fs.FS, name string) (*SiteConfig, error) {
+	raw, err := readConfig(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if theme, _ := raw["theme"].(string); theme != "" {
+		themeFile := path.Join(path.Dir(name), "themes", theme, "hugo.yaml")
+		themeRaw, err := readConfig(fsys, themeFile)
+		switch {
+		case err == nil:
+			mergeThemeConfig(raw, themeRaw)
+		case !errors.Is(err, fs.ErrNotExist):
+			return nil, err
+		}
+	}
+
+	return NewSiteConfig(raw)
+}
+
+// NewSiteConfig builds a SiteConfig from a decoded config map.
+func NewSiteConfig(raw map[string]any) (*SiteConfig, error) {
+	c := &SiteConfig{
+		Menus:  make(map[string][]MenuEntry),
+		Params: make(map[string]any),
+		Raw:    raw,
+	}
+	c.BaseURL, _ = raw["baseURL"].(string)
+	c.LanguageCode, _ = raw["languageCode"].(string)
+	c.Title, _ = raw["title"].(string)
+	c.Theme, _ = raw["theme"].(string)
+
+	if params, ok := raw["params"].(map[string]any); ok {
+		c.Params = params
+	}
+	c.Taxonomies = DefaultTaxonomies
+	if taxonomies, ok := raw["taxonomies"].(map[string]any); ok {
+		c.Taxonomies = make(map[string]string, len(taxonomies))
+		for singular, plural := range taxonomies {
+			if s, ok := plural.(string); ok && s != "" {
+				c.Taxonomies[singular] = s
+			}
+		}
+	}
+
+	permalinks, _ := raw["permalinks"].(map[string]any)
+	c.Permalinks = ParsePermalinks(permalinks)
+
+	menus, _ := raw["menus"].(map[string]any)
+	for name, value := range menus {
+		// Round trip through YAML to decode entries with their tags.
+		out, err := yaml.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("menu %s: %w", name, err)
+		}
+		var entries []MenuEntry
+		if err := yaml.Unmarshal(out, &entries); err != nil {
+			return nil, fmt.Errorf("menu %s: %w", name, err)
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			if entries[i].Weight != entries[j].Weight {
+				return entries[i].Weight < entries[j].Weight
+			}
+			return entries[i].Name < entries[j].Name
+		})
+		c.Menus[name] = entries
+	}
+
+	return c, nil
+}
+
+// Permalink returns the site relative URL of page.
+func (c *SiteConfig) Permalink(page *Page) string {
+	return c.Permalinks.URL(page)
+}
+
+// AbsURL joins a site relative URL with the base URL.
+func (c *SiteConfig) AbsURL(rel string) string {
+	base, err := url.Parse(c.BaseURL)
+	if err != nil || base.Host == "" {
+		return rel
+	}
+	return strings.TrimSuffix(base.String(), "/") + "/" + strings.TrimLeft(rel, "/")
+}
+
+// Menu returns the entries of a menu ordered by weight. Entries with a
+// pageRef get the URL of the referenced page when idx has it.
+func (c *SiteConfig) Menu(name string, idx *ContentIndex) []MenuEntry {
+	entries := make([]MenuEntry, 0, len(c.Menus[name]))
+	for _, entry := range c.Menus[name] {
+		if entry.URL == "" && entry.PageRef != "" {
+			entry.URL = strings.TrimSuffix(entry.PageRef, "/") + "/"
+			if idx != nil {
+				if page := idx.Lookup(entry.PageRef); page != nil {
+					entry.URL = c.Permalink(page)
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Param returns a site param by dotted key, such as "search.enable".
+func (c *SiteConfig) Param(key string) any {
+	var value any = c.Params
+	for part := range strings.SplitSeq(key, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = m[part]
+	}
+	return value
+}
+
+func readConfig(fsys fs.FS, name string) (map[string]any, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	raw := make(map[string]any)
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return raw, nil
+}
+
+// mergeThemeConfig merges a theme config into the project config in
+// place following themeMerge.
+func mergeThemeConfig(dst, theme map[string]any) {
+	for key, strategy := range themeMerge {
+		src, ok := theme[key].(map[string]any)
+		if !ok {
+			continue
+		}
+		cur, ok := dst[key].(map[string]any)
+		if !ok {
+			dst[key] = maps.Clone(src)
+			continue
+		}
+		if strategy == "deep" {
+			deepMerge(cur, src)
+			continue
+		}
+		for k, v := range src {
+			if _, exists := cur[k]; !exists {
+				cur[k] = v
+			}
+		}
+	}
+}
+
+// deepMerge adds entries from src missing in dst, recursing into maps
+// present in both.
+func deepMerge(dst, src map[string]any) {
+	for k, v := range src {
+		cur, exists := dst[k]
+		if !exists {
+			dst[k] = v
+			continue
+		}
+		curMap, ok1 := cur.(map[string]any)
+		srcMap, ok2 := v.(map[string]any)
+		if ok1 && ok2 {
+			deepMerge(curMap, srcMap)
+		}
+	}
+}
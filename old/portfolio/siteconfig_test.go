@@ -0,0 +1,125 @@
+package portfolio
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func siteFixture() fstest.MapFS {
+	return fstest.MapFS{
+		"hugo.yaml": {Data: []byte(joinLines(
+			"baseURL: 'https://jlrickert.me'",
+			"languageCode: 'en-us'",
+			"title: 'Site'",
+			"theme: nebula",
+			"permalinks:",
+			"  blog: /blog/:slug",
+			"params:",
+			"  author: Jared Rickert",
+			"  search:",
+			"    enable: true",
+			"menus:",
+			"  main:",
+			"    - name: Blog",
+			"      pageRef: /blog",
+			"      weight: 20",
+			"    - name: About",
+			"      pageRef: /about",
+			"      weight: 10",
+		))},
+		"themes/nebula/hugo.yaml": {Data: []byte(joinLines(
+			"baseURL: 'https://example.org/'",
+			"title: 'Theme'",
+			"params:",
+			"  math: true",
+			"  author: Theme Author",
+			"  search:",
+			"    limit: 10",
+			"menus:",
+			"  main:",
+			"    - name: Home",
+			"      pageRef: /",
+			"  footer:",
+			"    - name: Source",
+			"      url: https://github.com/jlrickert",
+			"permalinks:",
+			"  blog: /posts/:slug",
+			"  notes: /notes/:filename",
+		))},
+	}
+}
+
+func TestLoadSiteConfig(t *testing.T) {
+	site, err := LoadSiteConfig(siteFixture(), "hugo.yaml")
+	require.NoError(t, err)
+
+	// Root keys belong to the project.
+	assert.Equal(t, "https://jlrickert.me", site.BaseURL)
+	assert.Equal(t, "Site", site.Title)
+	assert.Equal(t, "en-us", site.LanguageCode)
+	assert.Equal(t, "nebula", site.Theme)
+
+	// Params merge deeply with the project winning.
+	assert.Equal(t, "Jared Rickert", site.Param("author"))
+	assert.Equal(t, true, site.Param("math"))
+	assert.Equal(t, true, site.Param("search.enable"))
+	assert.Equal(t, 10, site.Param("search.limit"))
+	assert.Nil(t, site.Param("search.missing.deeper"))
+
+	// Menus and permalinks only gain missing entries.
+	require.Len(t, site.Menus["main"], 2)
+	assert.Equal(t, "About", site.Menus["main"][0].Name)
+	assert.Equal(t, "Blog", site.Menus["main"][1].Name)
+	require.Len(t, site.Menus["footer"], 1)
+	assert.Equal(t, "https://github.com/jlrickert", site.Menus["footer"][0].URL)
+	assert.Equal(t, "/blog/:slug", site.Permalinks.Page["blog"])
+	assert.Equal(t, "/notes/:filename", site.Permalinks.Page["notes"])
+}
+
+func TestLoadSiteConfigWithoutTheme(t *testing.T) {
+	fsys := fstest.MapFS{
+		"hugo.yaml": {Data: []byte("title: Bare\ntheme: missing\n")},
+	}
+
+	site, err := LoadSiteConfig(fsys, "hugo.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "Bare", site.Title)
+	assert.Empty(t, site.Menus)
+
+	_, err = LoadSiteConfig(fstest.MapFS{}, "hugo.yaml")
+	assert.Error(t, err)
+}
+
+func TestSiteConfigMenu(t *testing.T) {
+	site, err := LoadSiteConfig(siteFixture(), "hugo.yaml")
+	require.NoError(t, err)
+
+	idx, err := NewContentIndex(context.Background(), fstest.MapFS{
+		"content/about.md": {Data: []byte("---\ntitle: About\nslug: me\n---\n")},
+	}, "content")
+	require.NoError(t, err)
+
+	menu := site.Menu("main", idx)
+	require.Len(t, menu, 2)
+	assert.Equal(t, "/me/", menu[0].URL)
+	assert.Equal(t, "/blog/", menu[1].URL)
+
+	assert.Equal(t, "/about/", site.Menu("main", nil)[0].URL)
+}
+
+func TestSiteConfigPermalink(t *testing.T) {
+	site, err := LoadSiteConfig(siteFixture(), "hugo.yaml")
+	require.NoError(t, err)
+
+	page := testPage("blog/first.md", "---\ntitle: First\nslug: hello\n---\n")
+	assert.Equal(t, "/blog/hello/", site.Permalink(page))
+	assert.Equal(t, "https://jlrickert.me/blog/hello/", site.AbsURL(site.Permalink(page)))
+
+	idx := &ContentIndex{pages: []*Page{page}}
+	idx.SetPermalinks(site.Permalinks)
+	assert.Equal(t, "/blog/hello/", page.URL())
+}
@@ -0,0 +1,383 @@
+package portfolio
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Proficiency describes how comfortable I am with a skill.
+type Proficiency string
+
+const (
+	ProficiencyBeginner     Proficiency = "beginner"
+	ProficiencyIntermediate Proficiency = "intermediate"
+	ProficiencyAdvanced     Proficiency = "advanced"
+	ProficiencyExpert       Proficiency = "expert"
+)
+
+// Skill represents a single skill entry.
+//
+// In data.yaml a skill may be written either as a plain string (the
+// original format) or as a mapping with the optional fields below.
+type Skill struct {
+	Name        string      `yaml:"name" json:"name"`
+	Category    string      `yaml:"category,omitempty" json:"category,omitempty"`
+	Proficiency Proficiency `yaml:"proficiency,omitempty" json:"proficiency,omitempty"`
+	FirstUsed   string      `yaml:"first_used,omitempty" json:"firstUsed,omitempty"`
+	LastUsed    string      `yaml:"last_used,omitempty" json:"lastUsed,omitempty"`
+	Aliases     []string    `yaml:"aliases,omitempty" json:"aliases,omitempty"`
+}
+
+// UnmarshalYAML accepts either a scalar skill name or a full mapping.
+func (s *Skill) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		s.Name = strings.TrimSpace(node.Value)
+		return nil
+	}
+
+	type rawSkill Skill
+	var raw rawSkill
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	if strings.TrimSpace(raw.Name) == "" {
+		return fmt.Errorf("line %d: skill is missing a name", node.Line)
+	}
+	*s = Skill(raw)
+	return nil
+}
+
+// String returns the skill name so templates can print a skill directly.
+func (s Skill) String() string {
+	return s.Name
+}
+
+// Anchor returns the id of the skill on the home page.
+func (s Skill) Anchor() string {
+	return "skill-" + urlize(s.Name)
+}
+
+// Matches reports whether name refers to this skill by name or alias.
+func (s Skill) Matches(name string) bool {
+	key := normalizeSkill(name)
+	if key == "" {
+		return false
+	}
+	if normalizeSkill(s.Name) == key {
+		return true
+	}
+	return slices.ContainsFunc(s.Aliases, func(alias string) bool {
+		return normalizeSkill(alias) == key
+	})
+}
+
+// MarshalJSON keeps the original JSON of the skills in /api/data, a
+// list of names per category. The structured skills are served by
+// /api/skills.
+func (s Skills) MarshalJSON() ([]byte, error) {
+	names := func(skills []Skill) []string {
+		var out []string
+		for _, skill := range skills {
+			out = append(out, skill.Name)
+		}
+		return out
+	}
+	return json.Marshal(struct {
+		Languages   []string
+		Frontend    []string
+		Backend     []string
+		CloudDevOps []string
+		Databases   []string
+		Tools       []string
+	}{
+		names(s.Languages),
+		names(s.Frontend),
+		names(s.Backend),
+		names(s.CloudDevOps),
+		names(s.Databases),
+		names(s.Tools),
+	})
+}
+
+// SkillCategory groups the skills of a single category.
+type SkillCategory struct {
+	Category string
+	Items    []Skill
+}
+
+// Categories returns the skill categories in data.yaml order with the
+// category set on each item.
+func (s Skills) Categories() []SkillCategory {
+	groups := []SkillCategory{
+		{Category: "languages", Items: s.Languages},
+		{Category: "frontend", Items: s.Frontend},
+		{Category: "backend", Items: s.Backend},
+		{Category: "cloud_devops", Items: s.CloudDevOps},
+		{Category: "databases", Items: s.Databases},
+		{Category: "tools", Items: s.Tools},
+	}
+	for i := range groups {
+		items := make([]Skill, len(groups[i].Items))
+		for j, skill := range groups[i].Items {
+			if skill.Category == "" {
+				skill.Category = groups[i].Category
+			}
+			items[j] = skill
+		}
+		groups[i].Items = items
+	}
+	return groups
+}
+
+// All returns every declared skill with its category populated.
+func (s Skills) All() []Skill {
+	var all []Skill
+	for _, group := range s.Categories() {
+		all = append(all, group.Items...)
+	}
+	return all
+}
+
+// TechnologyList parses the comma separated technologies string.
+func (e Experience) TechnologyList() []string {
+	var techs []string
+	for tech := range strings.SplitSeq(e.Technologies, ",") {
+		if tech = strings.TrimSpace(tech); tech != "" {
+			techs = append(techs, tech)
+		}
+	}
+	return techs
+}
+
+// Start returns the parsed start date of the experience.
+func (e Experience) Start() (time.Time, bool) {
+	return parseMonthYear(e.StartDate)
+}
+
+// End returns the parsed end date of the experience. Current positions
+// end at now.
+func (e Experience) End(now time.Time) (time.Time, bool) {
+	if e.Current || e.EndDate == "" {
+		return now, e.Current
+	}
+	return parseMonthYear(e.EndDate)
+}
+
+// SkillUsage links a skill to an experience entry where it was used.
+// End is exclusive: a position ending "May 2022" ends on June 1, and a
+// current one at now.
+type SkillUsage struct {
+	Experience int       `json:"experience"`
+	Company    string    `json:"company"`
+	Title      string    `json:"title"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Current    bool      `json:"current"`
+}
+
+// SkillProfile is a skill together with everywhere it has been used.
+type SkillProfile struct {
+	Skill
+	Usages []SkillUsage `json:"usages"`
+	Years  float64      `json:"years"`
+}
+
+// SkillIndex answers "where have I used X" queries over Data.
+type SkillIndex struct {
+	profiles []*SkillProfile
+	lookup   map[string]*SkillProfile
+}
+
+// NewSkillIndex builds a SkillIndex from the declared skills and the
+// technologies listed on each experience. Technologies that are not
+// declared as skills are added under the "other" category.
+func NewSkillIndex(data *Data, now time.Time) *SkillIndex {
+	idx := &SkillIndex{lookup: make(map[string]*SkillProfile)}
+
+	for _, skill := range data.Skills.All() {
+		idx.add(skill)
+	}
+
+	for i, exp := range data.Experience {
+		start, ok := exp.Start()
+		if !ok {
+			continue
+		}
+		end, _ := exp.End(now)
+		if !exp.Current {
+			// The end month was worked in full.
+			if _, next, ok := parseMonthYearPeriod(exp.EndDate); ok {
+				end = next
+			}
+		}
+		if end.IsZero() {
+			end = start
+		}
+		for _, tech := range exp.TechnologyList() {
+			profile := idx.Get(tech)
+			if profile == nil {
+				profile = idx.add(Skill{Name: tech, Category: "other"})
+			}
+			profile.Usages = append(profile.Usages, SkillUsage{
+				Experience: i,
+				Company:    exp.Company,
+				Title:      exp.Title,
+				Start:      start,
+				End:        end,
+				Current:    exp.Current,
+			})
+		}
+	}
+
+	for _, profile := range idx.profiles {
+		sort.Slice(profile.Usages, func(i, j int) bool {
+			return profile.Usages[i].Start.Before(profile.Usages[j].Start)
+		})
+		profile.Years = usageYears(profile.Usages)
+		if len(profile.Usages) == 0 {
+			continue
+		}
+		if profile.FirstUsed == "" {
+			profile.FirstUsed = profile.Usages[0].Start.Format("2006-01")
+		}
+		if profile.LastUsed == "" {
+			var last time.Time
+			for _, usage := range profile.Usages {
+				end := usage.End
+				if !usage.Current && end.After(usage.Start) {
+					end = end.AddDate(0, 0, -1)
+				}
+				if end.After(last) {
+					last = end
+				}
+			}
+			profile.LastUsed = last.Format("2006-01")
+		}
+	}
+
+	return idx
+}
+
+func (idx *SkillIndex) add(skill Skill) *SkillProfile {
+	profile := &SkillProfile{Skill: skill, Usages: []SkillUsage{}}
+	idx.profiles = append(idx.profiles, profile)
+	for _, key := range append([]string{skill.Name}, skill.Aliases...) {
+		if key = normalizeSkill(key); key != "" {
+			if _, exists := idx.lookup[key]; !exists {
+				idx.lookup[key] = profile
+			}
+		}
+	}
+	return profile
+}
+
+// Get returns the profile for a skill name or alias, or nil.
+func (idx *SkillIndex) Get(name string) *SkillProfile {
+	return idx.lookup[normalizeSkill(name)]
+}
+
+// All returns every skill profile in declaration order.
+func (idx *SkillIndex) All() []*SkillProfile {
+	return idx.profiles
+}
+
+// SkillProfileCategory groups the skill profiles of a single category.
+type SkillProfileCategory struct {
+	Category string
+	Items    []*SkillProfile
+}
+
+// Categories returns the profiles grouped by category in declaration
+// order, with undeclared technologies last under "other".
+func (idx *SkillIndex) Categories() []SkillProfileCategory {
+	var groups []SkillProfileCategory
+	at := make(map[string]int)
+	for _, profile := range idx.profiles {
+		i, ok := at[profile.Category]
+		if !ok {
+			i = len(groups)
+			at[profile.Category] = i
+			groups = append(groups, SkillProfileCategory{Category: profile.Category})
+		}
+		groups[i].Items = append(groups[i].Items, profile)
+	}
+	return groups
+}
+
+// WhereUsed returns the experiences where the named skill was used.
+func (idx *SkillIndex) WhereUsed(name string) []SkillUsage {
+	if profile := idx.Get(name); profile != nil {
+		return profile.Usages
+	}
+	return nil
+}
+
+// Years returns the years of experience with the named skill.
+// Overlapping positions are only counted once.
+func (idx *SkillIndex) Years(name string) float64 {
+	if profile := idx.Get(name); profile != nil {
+		return profile.Years
+	}
+	return 0
+}
+
+// usageYears sums the usage intervals, merging overlaps. Usages must be
+// sorted by start date.
+func usageYears(usages []SkillUsage) float64 {
+	var total time.Duration
+	var curStart, curEnd time.Time
+	for i, usage := range usages {
+		if i == 0 || usage.Start.After(curEnd) {
+			total += curEnd.Sub(curStart)
+			curStart, curEnd = usage.Start, usage.End
+			continue
+		}
+		if usage.End.After(curEnd) {
+			curEnd = usage.End
+		}
+	}
+	total += curEnd.Sub(curStart)
+
+	years := total.Hours() / 24 / 365.25
+	return float64(int(years*10+0.5)) / 10
+}
+
+// normalizeSkill returns the lookup key for a skill name.
+func normalizeSkill(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// parseMonthYear parses the loose dates used in data.yaml such as
+// "April 2024", "Nov 2023", "2024-04" or "2014".
+func parseMonthYear(value string) (time.Time, bool) {
+	start, _, ok := parseMonthYearPeriod(value)
+	return start, ok
+}
+
+// parseMonthYearPeriod parses a date as parseMonthYear does and also
+// returns the start of the next period, so "Nov 2023" spans until
+// December 1 and "2014" until the next year.
+func parseMonthYearPeriod(value string) (time.Time, time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []struct {
+		format              string
+		years, months, days int
+	}{
+		{"January 2006", 0, 1, 0},
+		{"Jan 2006", 0, 1, 0},
+		{"2006-01-02", 0, 0, 1},
+		{"2006-01", 0, 1, 0},
+		{"2006", 1, 0, 0},
+	} {
+		if t, err := time.Parse(layout.format, value); err == nil {
+			return t, t.AddDate(layout.years, layout.months, layout.days), true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
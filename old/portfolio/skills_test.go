@@ -0,0 +1,116 @@
+package portfolio
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const skillsYAML = `
+experience:
+  - title: Web Developer
+    company: Ecreative
+    start_date: April 2024
+    current: true
+    technologies: PHP, MySQL, Keycloak
+  - title: Full Stack Software Developer
+    company: Ecreativeworks
+    start_date: August 2020
+    end_date: May 2022
+    technologies: Go, golang, PHP
+  - title: Contractor
+    company: Acme
+    start_date: January 2022
+    end_date: June 2022
+    technologies: Go, Terraform
+skills:
+  languages:
+    - PHP
+    - name: Go
+      proficiency: expert
+      aliases: [golang]
+  backend:
+    - Keycloak
+`
+
+func TestSkillsBackwardCompatible(t *testing.T) {
+	data, err := LoadData([]byte(skillsYAML))
+	require.NoError(t, err)
+
+	require.Len(t, data.Skills.Languages, 2)
+	assert.Equal(t, "PHP", data.Skills.Languages[0].Name)
+	assert.Equal(t, "Go", data.Skills.Languages[1].String())
+	assert.Equal(t, ProficiencyExpert, data.Skills.Languages[1].Proficiency)
+	assert.True(t, data.Skills.Languages[1].Matches("GoLang"))
+}
+
+func TestSkillsJSON(t *testing.T) {
+	data, err := LoadData([]byte(skillsYAML))
+	require.NoError(t, err)
+
+	// /api/data keeps the original list of names per category.
+	out, err := json.Marshal(data.Skills)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"Languages": ["PHP", "Go"],
+		"Frontend": null,
+		"Backend": ["Keycloak"],
+		"CloudDevOps": null,
+		"Databases": null,
+		"Tools": null
+	}`, string(out))
+}
+
+func TestSkillsMissingName(t *testing.T) {
+	_, err := LoadData([]byte("skills:\n  languages:\n    - proficiency: expert\n"))
+	assert.Error(t, err)
+}
+
+func TestTechnologyList(t *testing.T) {
+	exp := Experience{Technologies: "PHP,  MySQL , ,Bash"}
+	assert.Equal(t, []string{"PHP", "MySQL", "Bash"}, exp.TechnologyList())
+}
+
+func TestSkillIndexWhereUsed(t *testing.T) {
+	data, err := LoadData([]byte(skillsYAML))
+	require.NoError(t, err)
+
+	now := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+	idx := NewSkillIndex(data, now)
+
+	usages := idx.WhereUsed("php")
+	require.Len(t, usages, 2)
+	assert.Equal(t, "Ecreativeworks", usages[0].Company)
+	assert.Equal(t, "Ecreative", usages[1].Company)
+	assert.True(t, usages[1].Current)
+
+	php := idx.Get("PHP")
+	require.NotNil(t, php)
+	assert.Equal(t, "languages", php.Category)
+	assert.Equal(t, "2020-08", php.FirstUsed)
+	assert.Equal(t, "2025-04", php.LastUsed)
+
+	terraform := idx.Get("terraform")
+	require.NotNil(t, terraform)
+	assert.Equal(t, "other", terraform.Category)
+
+	assert.Nil(t, idx.WhereUsed("cobol"))
+}
+
+func TestSkillIndexYears(t *testing.T) {
+	data, err := LoadData([]byte(skillsYAML))
+	require.NoError(t, err)
+
+	now := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+	idx := NewSkillIndex(data, now)
+
+	// Go is listed twice at Ecreativeworks (name and alias) and overlaps
+	// with Acme, so only Aug 2020 through the end of Jun 2022 is counted.
+	assert.Equal(t, 1.9, idx.Years("go"))
+	assert.Equal(t, "2022-06", idx.Get("go").LastUsed)
+	assert.Equal(t, 1.0, idx.Years("keycloak"))
+	assert.Equal(t, 0.0, idx.Years("cobol"))
+}
@@ -0,0 +1,206 @@
+package portfolio
+
+import "bytes"
+
+// stem reduces a lowercase English word to its stem with the Porter
+// algorithm, so "searching", "searched" and "searches" all index as
+// "search". Words with characters other than a-z are returned as is.
+func stem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+
+	w := []byte(word)
+	w = stemStep1a(w)
+	w = stemStep1b(w)
+	w = stemStep1c(w)
+	w = stemRules(w, stemStep2, 0)
+	w = stemRules(w, stemStep3, 0)
+	w = stemStep4(w)
+	w = stemStep5(w)
+	return string(w)
+}
+
+type stemRule struct {
+	suffix, repl string
+}
+
+var stemStep2 = []stemRule{
+	{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"},
+	{"izer", "ize"}, {"bli", "ble"}, {"alli", "al"}, {"entli", "ent"},
+	{"eli", "e"}, {"ousli", "ous"}, {"ization", "ize"}, {"ation", "ate"},
+	{"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"},
+	{"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+	{"logi", "log"},
+}
+
+var stemStep3 = []stemRule{
+	{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"},
+	{"ical", "ic"}, {"ful", ""}, {"ness", ""},
+}
+
+var stemStep4Suffixes = []string{
+	"ement", "ment", "ance", "ence", "able", "ible", "ant", "ent", "ism",
+	"ate", "iti", "ous", "ive", "ize", "ion", "al", "er", "ic", "ou",
+}
+
+// stemRules replaces the first matching suffix when the measure of what
+// precedes it is above minMeasure.
+func stemRules(w []byte, rules []stemRule, minMeasure int) []byte {
+	for _, r := range rules {
+		if bytes.HasSuffix(w, []byte(r.suffix)) {
+			stem := w[:len(w)-len(r.suffix)]
+			if stemMeasure(stem) > minMeasure {
+				return append(stem, r.repl...)
+			}
+			return w
+		}
+	}
+	return w
+}
+
+func stemStep1a(w []byte) []byte {
+	switch {
+	case bytes.HasSuffix(w, []byte("sses")), bytes.HasSuffix(w, []byte("ies")):
+		return w[:len(w)-2]
+	case bytes.HasSuffix(w, []byte("ss")):
+		return w
+	case bytes.HasSuffix(w, []byte("s")):
+		return w[:len(w)-1]
+	}
+	return w
+}
+
+func stemStep1b(w []byte) []byte {
+	if bytes.HasSuffix(w, []byte("eed")) {
+		if stemMeasure(w[:len(w)-3]) > 0 {
+			return w[:len(w)-1]
+		}
+		return w
+	}
+
+	var stem []byte
+	switch {
+	case bytes.HasSuffix(w, []byte("ed")):
+		stem = w[:len(w)-2]
+	case bytes.HasSuffix(w, []byte("ing")):
+		stem = w[:len(w)-3]
+	default:
+		return w
+	}
+	if !stemHasVowel(stem) {
+		return w
+	}
+
+	switch {
+	case bytes.HasSuffix(stem, []byte("at")),
+		bytes.HasSuffix(stem, []byte("bl")),
+		bytes.HasSuffix(stem, []byte("iz")):
+		return append(stem, 'e')
+	case stemEndsDouble(stem):
+		if last := stem[len(stem)-1]; last != 'l' && last != 's' && last != 'z' {
+			return stem[:len(stem)-1]
+		}
+	case stemMeasure(stem) == 1 && stemCVC(stem):
+		return append(stem, 'e')
+	}
+	return stem
+}
+
+func stemStep1c(w []byte) []byte {
+	if w[len(w)-1] == 'y' && stemHasVowel(w[:len(w)-1]) {
+		w[len(w)-1] = 'i'
+	}
+	return w
+}
+
+func stemStep4(w []byte) []byte {
+	for _, suffix := range stemStep4Suffixes {
+		if !bytes.HasSuffix(w, []byte(suffix)) {
+			continue
+		}
+		stem := w[:len(w)-len(suffix)]
+		if suffix == "ion" && (len(stem) == 0 || (stem[len(stem)-1] != 's' && stem[len(stem)-1] != 't')) {
+			return w
+		}
+		if stemMeasure(stem) > 1 {
+			return stem
+		}
+		return w
+	}
+	return w
+}
+
+func stemStep5(w []byte) []byte {
+	if w[len(w)-1] == 'e' {
+		stem := w[:len(w)-1]
+		if m := stemMeasure(stem); m > 1 || (m == 1 && !stemCVC(stem)) {
+			w = stem
+		}
+	}
+	if stemMeasure(w) > 1 && stemEndsDouble(w) && w[len(w)-1] == 'l' {
+		w = w[:len(w)-1]
+	}
+	return w
+}
+
+func stemConsonant(w []byte, i int) bool {
+	switch w[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !stemConsonant(w, i-1)
+	}
+	return true
+}
+
+// stemMeasure counts the vowel-consonant sequences in w.
+func stemMeasure(w []byte) int {
+	n, i := 0, 0
+	for i < len(w) && stemConsonant(w, i) {
+		i++
+	}
+	for i < len(w) {
+		for i < len(w) && !stemConsonant(w, i) {
+			i++
+		}
+		if i == len(w) {
+			break
+		}
+		for i < len(w) && stemConsonant(w, i) {
+			i++
+		}
+		n++
+	}
+	return n
+}
+
+func stemHasVowel(w []byte) bool {
+	for i := range w {
+		if !stemConsonant(w, i) {
+			return true
+		}
+	}
+	return false
+}
+
+func stemEndsDouble(w []byte) bool {
+	n := len(w)
+	return n >= 2 && w[n-1] == w[n-2] && stemConsonant(w, n-1)
+}
+
+// stemCVC reports whether w ends consonant-vowel-consonant where the
+// last consonant is not w, x or y, as in "hop".
+func stemCVC(w []byte) bool {
+	n := len(w)
+	if n < 3 || !stemConsonant(w, n-3) || stemConsonant(w, n-2) || !stemConsonant(w, n-1) {
+		return false
+	}
+	last := w[n-1]
+	return last != 'w' && last != 'x' && last != 'y'
+}
@@ -0,0 +1,36 @@
+package portfolio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStem(t *testing.T) {
+	cases := map[string]string{
+		"caresses":       "caress",
+		"ponies":         "poni",
+		"cats":           "cat",
+		"feed":           "feed",
+		"agreed":         "agre",
+		"plastered":      "plaster",
+		"motoring":       "motor",
+		"sing":           "sing",
+		"hopping":        "hop",
+		"filing":         "file",
+		"happy":          "happi",
+		"relational":     "relat",
+		"generalization": "gener",
+		"searching":      "search",
+		"searches":       "search",
+		"deployments":    "deploy",
+		"adjustment":     "adjust",
+		"controll":       "control",
+		"go":             "go",
+		"node.js":        "node.js",
+		"k8s":            "k8s",
+	}
+	for word, want := range cases {
+		assert.Equal(t, want, stem(word), word)
+	}
+}
@@ -0,0 +1,211 @@
+package portfolio
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultTaxonomies are Hugo's default taxonomies, singular to plural.
+var DefaultTaxonomies = map[string]string{
+	"tag":      "tags",
+	"category": "categories",
+}
+
+// DefaultTermAliases fold common spellings of a term into one.
+var DefaultTermAliases = map[string]string{
+	"golang": "go",
+}
+
+// Term is a taxonomy term and the pages that use it.
+type Term struct {
+	// Name is the most common spelling used in front matter.
+	Name string `json:"name"`
+	// Key is the normalized term used for lookups and URLs.
+	Key   string  `json:"key"`
+	Count int     `json:"count"`
+	Pages []*Page `json:"-"`
+
+	spellings map[string]int
+}
+
+// Taxonomy maps the terms of one taxonomy, such as tags, to pages.
+type Taxonomy struct {
+	Name    string
+	terms   map[string]*Term
+	aliases map[string]string
+}
+
+// Taxonomies holds every configured taxonomy keyed by plural name.
+type Taxonomies map[string]*Taxonomy
+
+// NewTaxonomies builds the named taxonomies over pages. Terms are
+// normalized with NormalizeTerm so "Go", "go" and "golang" (through
+// aliases) share one term.
+func NewTaxonomies(pages []*Page, names []string, aliases map[string]string) Taxonomies {
+	taxonomies := make(Taxonomies, len(names))
+	for _, name := range names {
+		tax := &Taxonomy{Name: name, terms: make(map[string]*Term), aliases: aliases}
+		for _, page := range pages {
+			seen := make(map[string]bool)
+			for _, value := range page.Terms(name) {
+				key := NormalizeTerm(value, aliases)
+				if key == "" || seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				term := tax.terms[key]
+				if term == nil {
+					term = &Term{Key: key, spellings: make(map[string]int)}
+					tax.terms[key] = term
+				}
+				term.Pages = append(term.Pages, page)
+				term.Count++
+				term.spellings[strings.TrimSpace(value)]++
+			}
+		}
+		for _, term := range tax.terms {
+			term.Name = preferredSpelling(term.spellings)
+			sort.SliceStable(term.Pages, func(i, j int) bool {
+				return term.Pages[i].Date().After(term.Pages[j].Date())
+			})
+		}
+		taxonomies[name] = tax
+	}
+	return taxonomies
+}
+
+// Terms returns the terms ordered by page count, most used first.
+func (t *Taxonomy) Terms() []*Term {
+	terms := t.Alphabetical()
+	sort.SliceStable(terms, func(i, j int) bool {
+		return terms[i].Count > terms[j].Count
+	})
+	return terms
+}
+
+// Alphabetical returns the terms ordered by key.
+func (t *Taxonomy) Alphabetical() []*Term {
+	terms := make([]*Term, 0, len(t.terms))
+	for _, term := range t.terms {
+		terms = append(terms, term)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		return terms[i].Key < terms[j].Key
+	})
+	return terms
+}
+
+// Get returns the term matching name after normalization, or nil.
+func (t *Taxonomy) Get(name string) *Term {
+	return t.terms[NormalizeTerm(name, t.aliases)]
+}
+
+// Terms returns the raw values of a taxonomy in the page front matter.
+func (p *Page) Terms(taxonomy string) []string {
+	if taxonomy == "tags" {
+		return p.Tags()
+	}
+	values, err := asStrings(p.Meta[taxonomy])
+	if err != nil {
+		return nil
+	}
+	return values
+}
+
+// NormalizeTerm lowercases a term and joins words with single hyphens,
+// so "Web Dev", "web_dev" and "web-dev" are the same term. Characters
+// meaningful in technology names such as "+", "#" and "." are kept.
+func NormalizeTerm(value string, aliases map[string]string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(value)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("+#.", r):
+			b.WriteRune(r)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	key := strings.TrimSuffix(b.String(), "-")
+	if alias, ok := aliases[key]; ok {
+		return alias
+	}
+	return key
+}
+
+// preferredSpelling returns the most used spelling, breaking ties
+// alphabetically so the result is stable.
+func preferredSpelling(spellings map[string]int) string {
+	best, count := "", 0
+	for spelling, n := range spellings {
+		if n > count || (n == count && spelling < best) {
+			best, count = spelling, n
+		}
+	}
+	return best
+}
+
+// TaxonomyNames returns the plural names of the configured taxonomies.
+func (c *SiteConfig) TaxonomyNames() []string {
+	names := make([]string, 0, len(c.Taxonomies))
+	for _, plural := range c.Taxonomies {
+		names = append(names, plural)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TermAliases returns DefaultTermAliases extended by the termAliases
+// site param.
+func (c *SiteConfig) TermAliases() map[string]string {
+	aliases := make(map[string]string, len(DefaultTermAliases))
+	for from, to := range DefaultTermAliases {
+		aliases[from] = to
+	}
+	if extra, ok := c.Params["termAliases"].(map[string]any); ok {
+		for from, to := range extra {
+			if s, ok := to.(string); ok {
+				aliases[NormalizeTerm(from, nil)] = NormalizeTerm(s, nil)
+			}
+		}
+	}
+	return aliases
+}
+
+// Taxonomies returns the configured taxonomies over the visible regular
+// pages. They are built with the content index and replaced when it is
+// reloaded or the publication state changes.
+func (m *AssetManager) Taxonomies(ctx context.Context) (Taxonomies, error) {
+	if _, err := m.Index(ctx); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.taxonomies, nil
+}
+
+// SyncTaxonomies rebuilds the taxonomies of the loaded index so pages
+// that were published or expired since it was loaded are counted.
+func (m *AssetManager) SyncTaxonomies() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.index != nil && m.site != nil {
+		m.taxonomies = m.buildTaxonomies(m.index, m.site)
+	}
+}
+
+func (m *AssetManager) buildTaxonomies(idx *ContentIndex, site *SiteConfig) Taxonomies {
+	var pages []*Page
+	for _, page := range idx.Pages() {
+		if page.Kind == KindPage {
+			pages = append(pages, page)
+		}
+	}
+	pages = m.Publish.Filter(pages, m.now())
+	return NewTaxonomies(pages, site.TaxonomyNames(), site.TermAliases())
+}
@@ -0,0 +1,154 @@
+package portfolio
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTerm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Go", "go"},
+		{"golang", "go"},
+		{"GoLang", "go"},
+		{"Web Dev", "web-dev"},
+		{"web_dev", "web-dev"},
+		{" web--dev ", "web-dev"},
+		{"C++", "c++"},
+		{"C#", "c#"},
+		{"Node.js", "node.js"},
+		{"!!!", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeTerm(tt.input, DefaultTermAliases))
+		})
+	}
+}
+
+func TestNewTaxonomies(t *testing.T) {
+	pages := []*Page{
+		testPage("blog/a.md", "---\ntitle: A\ndate: 2025-01-01\ntags: [Go, Web Dev]\ncategories: [notes]\n---\n"),
+		testPage("blog/b.md", "---\ntitle: B\ndate: 2025-03-01\ntags: [go, golang, web-dev]\nseries: Keg\n---\n"),
+		testPage("blog/c.md", "---\ntitle: C\ndate: 2025-02-01\ntags: [go]\n---\n"),
+		testPage("blog/d.md", "---\ntitle: D\ndate: 2025-04-01\n---\n"),
+	}
+
+	taxonomies := NewTaxonomies(pages, []string{"tags", "categories", "series"}, DefaultTermAliases)
+
+	tags := taxonomies["tags"]
+	require.NotNil(t, tags)
+	terms := tags.Terms()
+	require.Len(t, terms, 2)
+
+	assert.Equal(t, "go", terms[0].Key)
+	assert.Equal(t, "go", terms[0].Name)
+	assert.Equal(t, 3, terms[0].Count)
+	assert.Equal(t, "B", terms[0].Pages[0].Title())
+	assert.Equal(t, "C", terms[0].Pages[1].Title())
+	assert.Equal(t, "A", terms[0].Pages[2].Title())
+
+	assert.Equal(t, "web-dev", terms[1].Key)
+	assert.Equal(t, 2, terms[1].Count)
+
+	assert.Same(t, terms[0], tags.Get("Golang"))
+	assert.Same(t, terms[1], tags.Get("web dev"))
+	assert.Nil(t, tags.Get("rust"))
+
+	assert.Equal(t, 1, taxonomies["categories"].Get("notes").Count)
+	assert.Equal(t, "Keg", taxonomies["series"].Get("keg").Name)
+}
+
+func TestSiteConfigTaxonomies(t *testing.T) {
+	site, err := NewSiteConfig(map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"categories", "tags"}, site.TaxonomyNames())
+
+	site, err = NewSiteConfig(map[string]any{
+		"taxonomies": map[string]any{"tag": "tags", "series": "series"},
+		"params": map[string]any{
+			"termAliases": map[string]any{"JS": "JavaScript"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"series", "tags"}, site.TaxonomyNames())
+	assert.Equal(t, "javascript", NormalizeTerm("js", site.TermAliases()))
+	assert.Equal(t, "go", NormalizeTerm("golang", site.TermAliases()))
+}
+
+func TestHandleTaxonomy(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), nil)
+
+	req := httptest.NewRequest("GET", "/tags", nil)
+	req.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var body struct {
+		Taxonomy string        `json:"taxonomy"`
+		Terms    []TermSummary `json:"terms"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Equal(t, "tags", body.Taxonomy)
+	require.NotEmpty(t, body.Terms)
+	for i := 1; i < len(body.Terms); i++ {
+		assert.GreaterOrEqual(t, body.Terms[i-1].Count, body.Terms[i].Count)
+	}
+
+	term := body.Terms[0]
+	req = httptest.NewRequest("GET", term.URL+"?format=json", nil)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var detail struct {
+		Term  TermSummary   `json:"term"`
+		Pages []PostSummary `json:"pages"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&detail))
+	assert.Equal(t, term, detail.Term)
+	assert.Len(t, detail.Pages, term.Count)
+
+	req = httptest.NewRequest("GET", term.URL, nil)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), detail.Pages[0].URL)
+
+	req = httptest.NewRequest("GET", "/tags/no-such-term?format=json", nil)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandleTaxonomyDropped(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), nil)
+
+	taxonomies, err := server.assetManager.Taxonomies(context.Background())
+	require.NoError(t, err)
+	again, err := server.assetManager.Taxonomies(context.Background())
+	require.NoError(t, err)
+	assert.Same(t, taxonomies["tags"], again["tags"])
+
+	// A reload that no longer configures tags leaves routes for it.
+	delete(taxonomies, "tags")
+	for _, target := range []string{"/tags", "/tags/php", "/tags/php/feed.json"} {
+		req := httptest.NewRequest("GET", target+"?format=json", nil)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code, target)
+	}
+}
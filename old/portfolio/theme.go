@@ -1,10 +1,367 @@
 package portfolio
 
-import "html/template"
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"maps"
+	"net/http"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
 
+// ThemeManager discovers the themes under themes/ and caches their
+// parsed templates. Every page template in a theme's templates
+// directory is parsed once, together with the theme's baseof.html and
+// the partials in its partials directory, into its own template set.
+// Partials are named after their file, as in
+// {{ template "partials/nav" . }}.
+//
+// Each theme may have a ThemeManifest. A theme with a parent inherits
+// the templates, partials, static files, params and asset bundles it
+// does not override. A theme is valid when its manifest is valid and
+// its baseof.html parses. Templates missing from a theme, or failing to
+// parse, are taken from the default theme.
 type ThemeManager struct {
+	assets       fs.FS
+	funcs        template.FuncMap
+	params       map[string]any
 	defaultTheme string
-	validThemes  map[string]bool
 
-	templates map[string]*template.Template // cache
+	mu          sync.RWMutex
+	validThemes map[string]bool
+	manifests   map[string]*ThemeManifest
+	chains      map[string][]string
+	templates   map[string]map[string]*template.Template // cache
+}
+
+// NewThemeManager loads every theme in assets. params override the
+// params of every theme, such as the theme map of the site params.
+// funcs are added to TemplateFuncs for all templates, along with:
+//
+//	theme        the theme's ThemeManifest, with inherited and overridden params
+//	themeParam   a theme param by dotted key
+//	themeAssets  the URLs of the files in an asset bundle
+//
+// Manifest and parse errors are returned together, so they are reported
+// at startup rather than per request. The manager is usable even then:
+// it serves whatever loaded.
+func NewThemeManager(assets fs.FS, defaultTheme string, params map[string]any, funcs template.FuncMap) (*ThemeManager, error) {
+	m := &ThemeManager{
+		assets:       assets,
+		funcs:        funcs,
+		params:       params,
+		defaultTheme: defaultTheme,
+	}
+	return m, m.Reload()
+}
+
+// Reload parses the themes again, such as after they are edited on
+// disk. The previous templates are kept when the default theme fails
+// to load.
+func (m *ThemeManager) Reload() error {
+	entries, err := fs.ReadDir(m.assets, "themes")
+	if err != nil {
+		return fmt.Errorf("failed to read themes: %w", err)
+	}
+
+	var errs []error
+	manifests := make(map[string]*ThemeManifest)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		manifest, err := LoadThemeManifest(m.assets, path.Join("themes", entry.Name()))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("theme %s: %w", entry.Name(), err))
+			continue
+		}
+		manifests[entry.Name()] = manifest
+	}
+
+	valid := make(map[string]bool)
+	resolved := make(map[string]*ThemeManifest)
+	chains := make(map[string][]string)
+	templates := make(map[string]map[string]*template.Template)
+	for theme := range manifests {
+		chain, err := themeChain(manifests, theme)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("theme %s: %w", theme, err))
+			continue
+		}
+		manifest := mergeManifests(manifests, chain)
+		mergeParams(manifest.Params, m.params)
+		set, err := m.parseTheme(theme, chain, manifest)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if set != nil {
+			valid[theme] = true
+			resolved[theme] = manifest
+			chains[theme] = chain
+			templates[theme] = set
+		}
+	}
+	if !valid[m.defaultTheme] {
+		errs = append(errs, fmt.Errorf("default theme %q is not available", m.defaultTheme))
+		return errors.Join(errs...)
+	}
+
+	m.mu.Lock()
+	m.validThemes = valid
+	m.manifests = resolved
+	m.chains = chains
+	m.templates = templates
+	m.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+// parseTheme parses the page templates of a theme. The set is nil when
+// the theme has no usable baseof.html or declares something missing;
+// page templates that fail are left out and reported.
+func (m *ThemeManager) parseTheme(theme string, chain []string, manifest *ThemeManifest) (map[string]*template.Template, error) {
+	pages, err := m.inheritedFiles(chain, "templates")
+	if err != nil {
+		return nil, fmt.Errorf("theme %s: %w", theme, err)
+	}
+	partials, err := m.inheritedFiles(chain, "partials")
+	if err != nil {
+		return nil, fmt.Errorf("theme %s: %w", theme, err)
+	}
+	baseFile, ok := pages["baseof"]
+	if !ok {
+		// Not a theme, such as a directory holding only static files.
+		return nil, nil
+	}
+	delete(pages, "baseof")
+
+	var errs []error
+	for _, name := range manifest.Templates {
+		if _, ok := pages[name]; !ok && name != "baseof" {
+			errs = append(errs, fmt.Errorf("theme %s: declared template %q does not exist", theme, name))
+		}
+	}
+	for _, name := range manifest.Partials {
+		if _, ok := partials[name]; !ok {
+			errs = append(errs, fmt.Errorf("theme %s: declared partial %q does not exist", theme, name))
+		}
+	}
+	for _, bundle := range slices.Sorted(maps.Keys(manifest.Assets)) {
+		for _, file := range manifest.Assets[bundle] {
+			if _, ok := m.staticPath(chain, file); !ok {
+				errs = append(errs, fmt.Errorf("theme %s: asset %q in bundle %s does not exist", theme, file, bundle))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	baseContent, err := fs.ReadFile(m.assets, baseFile)
+	if err != nil {
+		return nil, fmt.Errorf("theme %s: %w", theme, err)
+	}
+	base, err := template.New("baseof").
+		Funcs(TemplateFuncs()).
+		Funcs(m.funcs).
+		Funcs(themeFuncs(manifest)).
+		Parse(string(baseContent))
+	if err != nil {
+		return nil, fmt.Errorf("theme %s: failed to parse %s: %w", theme, baseFile, err)
+	}
+	for _, name := range slices.Sorted(maps.Keys(partials)) {
+		content, err := fs.ReadFile(m.assets, partials[name])
+		if err == nil {
+			_, err = base.New("partials/" + name).Parse(string(content))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("theme %s: failed to parse %s: %w", theme, partials[name], err)
+		}
+	}
+
+	set := make(map[string]*template.Template)
+	for _, name := range slices.Sorted(maps.Keys(pages)) {
+		content, err := fs.ReadFile(m.assets, pages[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("theme %s: %w", theme, err))
+			continue
+		}
+		tmpl, err := template.Must(base.Clone()).New(name).Parse(string(content))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("theme %s: failed to parse %s: %w", theme, pages[name], err))
+			continue
+		}
+		set[name] = tmpl
+	}
+	return set, errors.Join(errs...)
+}
+
+// inheritedFiles maps the names of the .html files in dir of each theme
+// in chain to their paths, children overriding their parents.
+func (m *ThemeManager) inheritedFiles(chain []string, dir string) (map[string]string, error) {
+	files := make(map[string]string)
+	for _, theme := range chain {
+		matches, err := fs.Glob(m.assets, path.Join("themes", theme, dir, "*.html"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range matches {
+			files[strings.TrimSuffix(path.Base(file), ".html")] = file
+		}
+	}
+	return files, nil
+}
+
+// staticPath finds a static file in the closest theme of chain that
+// has it.
+func (m *ThemeManager) staticPath(chain []string, name string) (string, bool) {
+	for i := len(chain) - 1; i >= 0; i-- {
+		p := path.Join("themes", chain[i], "static", name)
+		if info, err := fs.Stat(m.assets, p); err == nil && !info.IsDir() {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// themeFuncs are the template functions describing a theme.
+func themeFuncs(manifest *ThemeManifest) template.FuncMap {
+	return template.FuncMap{
+		"theme": func() *ThemeManifest {
+			return manifest
+		},
+		"themeParam":  manifest.Param,
+		"themeAssets": manifest.AssetURLs,
+	}
+}
+
+// Default returns the name of the default theme.
+func (m *ThemeManager) Default() string {
+	return m.defaultTheme
+}
+
+// Themes returns the names of the valid themes.
+func (m *ThemeManager) Themes() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.validThemes))
+	for name := range m.validThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Manifest returns the manifest of a valid theme with its inherited
+// asset bundles and its inherited and overridden params.
+func (m *ThemeManager) Manifest(theme string) (*ThemeManifest, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	manifest, ok := m.manifests[theme]
+	return manifest, ok
+}
+
+// StaticPath returns the path within the assets of a static file of
+// theme, looking through its ancestors and then the default theme.
+func (m *ThemeManager) StaticPath(theme, name string) (string, bool) {
+	if !fs.ValidPath(name) {
+		return "", false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if p, ok := m.staticPath(m.chains[theme], name); ok {
+		return p, true
+	}
+	return m.staticPath(m.chains[m.defaultTheme], name)
+}
+
+// IsValid reports whether theme was found and parsed.
+func (m *ThemeManager) IsValid(theme string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.validThemes[theme]
+}
+
+// Template returns the cached template set for a page template of
+// theme. An unknown theme, or a template the theme lacks, falls back to
+// the default theme.
+func (m *ThemeManager) Template(theme, name string) (*template.Template, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if tmpl, ok := m.templates[theme][name]; ok {
+		return tmpl, nil
+	}
+	if tmpl, ok := m.templates[m.defaultTheme][name]; ok {
+		return tmpl, nil
+	}
+	return nil, fmt.Errorf("template %q not found in theme %q", name, theme)
+}
+
+// Render executes the page template name of theme within its baseof
+// layout.
+func (m *ThemeManager) Render(w io.Writer, theme, name string, data any) error {
+	tmpl, err := m.Template(theme, name)
+	if err != nil {
+		return err
+	}
+	// Render to a buffer so a failed template writes nothing.
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "baseof", data); err != nil {
+		return err
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// RenderPartial executes the partial name of theme on its own, such as
+// for an htmx fragment. Every page template set holds the partials, so
+// the set of the index template is used.
+func (m *ThemeManager) RenderPartial(w io.Writer, theme, name string, data any) error {
+	tmpl, err := m.Template(theme, "index")
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "partials/"+name, data); err != nil {
+		return err
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// ThemeCookie is the cookie holding a visitor's chosen theme.
+const ThemeCookie = "theme"
+
+type themeContextKey struct{}
+
+// WithTheme returns a copy of ctx carrying the theme to render with.
+func WithTheme(ctx context.Context, theme string) context.Context {
+	return context.WithValue(ctx, themeContextKey{}, theme)
+}
+
+// ThemeFromContext returns the theme set by WithTheme, if any.
+func ThemeFromContext(ctx context.Context) (string, bool) {
+	theme, ok := ctx.Value(themeContextKey{}).(string)
+	return theme, ok
+}
+
+// Resolve picks the theme for a request: the default, replaced by the
+// theme cookie, replaced in turn by a ?theme= query override. Values
+// that are not valid themes are ignored.
+func (m *ThemeManager) Resolve(r *http.Request) string {
+	theme := m.defaultTheme
+	if cookie, err := r.Cookie(ThemeCookie); err == nil && m.IsValid(cookie.Value) {
+		theme = cookie.Value
+	}
+	if override := r.URL.Query().Get("theme"); m.IsValid(override) {
+		theme = override
+	}
+	return theme
 }
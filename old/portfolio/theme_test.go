@@ -0,0 +1,69 @@
+package portfolio
+
+import (
+	"bytes"
+	"html/template"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func themeFixture() fstest.MapFS {
+	return fstest.MapFS{
+		"themes/base/templates/baseof.html":   {Data: []byte(`<body class="base">{{ template "main" . }}</body>`)},
+		"themes/base/templates/index.html":    {Data: []byte(`{{ define "main" }}home {{ .Name | humanize }}{{ end }}`)},
+		"themes/base/templates/404.html":      {Data: []byte(`{{ define "main" }}missing{{ end }}`)},
+		"themes/light/templates/baseof.html":  {Data: []byte(`<body class="light">{{ template "main" . }} {{ site }}</body>`)},
+		"themes/light/templates/index.html":   {Data: []byte(`{{ define "main" }}light {{ .Name }}{{ end }}`)},
+		"themes/light/templates/404.html":     {Data: []byte(`{{ define "main" }}{{ .Name {{ end }}`)},
+		"themes/broken/templates/baseof.html": {Data: []byte(`{{ if }}`)},
+		"themes/assets-only/static/app.css":   {Data: []byte("body{}")},
+	}
+}
+
+func TestThemeManager(t *testing.T) {
+	funcs := template.FuncMap{"site": func() string { return "jlrickert.me" }}
+	themes, err := NewThemeManager(themeFixture(), "base", nil, funcs)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "theme broken: failed to parse themes/broken/templates/baseof.html")
+	assert.ErrorContains(t, err, "theme light: failed to parse themes/light/templates/404.html")
+
+	assert.Equal(t, []string{"base", "light"}, themes.Themes())
+	assert.True(t, themes.IsValid("light"))
+	assert.False(t, themes.IsValid("broken"))
+	assert.False(t, themes.IsValid("assets-only"))
+
+	tests := []struct {
+		theme, name, want string
+	}{
+		{"base", "index", `<body class="base">home Green Nebula</body>`},
+		{"light", "index", `<body class="light">light green-nebula jlrickert.me</body>`},
+		{"light", "404", `<body class="base">missing</body>`},
+		{"broken", "index", `<body class="base">home Green Nebula</body>`},
+		{"unknown", "404", `<body class="base">missing</body>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.theme+"/"+tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, themes.Render(&buf, tt.theme, tt.name, map[string]string{"Name": "green-nebula"}))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+
+	var buf bytes.Buffer
+	assert.ErrorContains(t, themes.Render(&buf, "base", "single", nil), `template "single" not found`)
+
+	// Template sets are parsed once and reused.
+	first, err := themes.Template("base", "index")
+	require.NoError(t, err)
+	second, err := themes.Template("base", "index")
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+}
+
+func TestThemeManagerMissingDefault(t *testing.T) {
+	_, err := NewThemeManager(themeFixture(), "dark", nil, nil)
+	assert.ErrorContains(t, err, `default theme "dark" is not available`)
+}
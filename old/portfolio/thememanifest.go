@@ -0,0 +1,216 @@
+package portfolio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"path"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ThemeManifestFile is the manifest file at the root of a theme.
+const ThemeManifestFile = "theme.yaml"
+
+// ThemeManifest describes a theme:
+//
+//	name: Green Nebula Terminal
+//	version: 0.1.0
+//	parent: base-terminal
+//	templates: [index, single]
+//	partials: [nav]
+//	assets:
+//	  css: [css/stylesheet.css]
+//	params:
+//	  prompt: "dev@portfolio:~$"
+//
+// A theme with a parent only needs the templates, partials and static
+// files it changes; the rest are inherited. Templates and partials are
+// named without the .html extension, and assets are paths under the
+// theme's static directory.
+type ThemeManifest struct {
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	Author      string `yaml:"author"`
+	Description string `yaml:"description"`
+	// Parent is the directory name of the theme this one extends.
+	Parent string `yaml:"parent"`
+	// Templates and Partials list those the theme provides or relies
+	// on. Each must exist in the theme or one of its ancestors.
+	Templates []string `yaml:"templates"`
+	Partials  []string `yaml:"partials"`
+	// Assets are named bundles of static files, such as the stylesheets
+	// of the theme. A bundle replaces the parent's bundle of the same
+	// name.
+	Assets map[string][]string `yaml:"assets"`
+	// Params are the configurable parameters of the theme with their
+	// defaults. A child theme overrides its parent's defaults, and the
+	// theme map of the site params overrides both.
+	Params map[string]any `yaml:"params"`
+}
+
+var (
+	manifestVersion = regexp.MustCompile(`^\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?$`)
+	manifestKey     = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+)
+
+// LoadThemeManifest reads the manifest of the theme in dir. A theme
+// without a manifest gets one named after the directory.
+func LoadThemeManifest(fsys fs.FS, dir string) (*ThemeManifest, error) {
+	data, err := fs.ReadFile(fsys, path.Join(dir, ThemeManifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return &ThemeManifest{Name: path.Base(dir)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := ParseThemeManifest(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path.Join(dir, ThemeManifestFile), err)
+	}
+	if manifest.Parent == path.Base(dir) {
+		return nil, fmt.Errorf("%s: theme is its own parent", path.Join(dir, ThemeManifestFile))
+	}
+	return manifest, nil
+}
+
+// ParseThemeManifest decodes and validates a manifest. Unknown fields
+// are rejected so typos do not go unnoticed.
+func ParseThemeManifest(data []byte) (*ThemeManifest, error) {
+	var manifest ThemeManifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&manifest); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// Validate checks the manifest on its own. Whether the templates,
+// partials and assets exist is checked when the theme is loaded.
+func (t *ThemeManifest) Validate() error {
+	var errs []error
+	if strings.TrimSpace(t.Name) == "" {
+		errs = append(errs, errors.New("missing name"))
+	}
+	if t.Version != "" && !manifestVersion.MatchString(t.Version) {
+		errs = append(errs, fmt.Errorf("version %q is not a semantic version", t.Version))
+	}
+	if t.Parent != "" && (strings.ContainsAny(t.Parent, `/\`) || t.Parent == "." || t.Parent == "..") {
+		errs = append(errs, fmt.Errorf("parent %q is not a theme directory name", t.Parent))
+	}
+	for _, name := range t.Templates {
+		if err := validateManifestName("template", name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, name := range t.Partials {
+		if err := validateManifestName("partial", name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for bundle, files := range t.Assets {
+		if !manifestKey.MatchString(bundle) {
+			errs = append(errs, fmt.Errorf("asset bundle %q is not a valid name", bundle))
+		}
+		for _, file := range files {
+			if file == "" || !fs.ValidPath(file) {
+				errs = append(errs, fmt.Errorf("asset %q in bundle %s is not a path within static", file, bundle))
+			}
+		}
+	}
+	for key := range t.Params {
+		if !manifestKey.MatchString(key) {
+			errs = append(errs, fmt.Errorf("param %q is not a valid name", key))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateManifestName(kind, name string) error {
+	if name == "" || strings.HasSuffix(name, ".html") || !fs.ValidPath(name) || strings.Contains(name, "/") {
+		return fmt.Errorf("%s %q should be a file name without .html", kind, name)
+	}
+	return nil
+}
+
+// Param returns a parameter by dotted key, as SiteConfig.Param does.
+func (t *ThemeManifest) Param(key string) any {
+	var value any = t.Params
+	for part := range strings.SplitSeq(key, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = m[part]
+	}
+	return value
+}
+
+// AssetURLs returns the site URLs of the files in an asset bundle.
+func (t *ThemeManifest) AssetURLs(bundle string) []string {
+	urls := make([]string, 0, len(t.Assets[bundle]))
+	for _, file := range t.Assets[bundle] {
+		urls = append(urls, "/static/"+file)
+	}
+	return urls
+}
+
+// themeChain returns the directory names of theme and its ancestors,
+// the root ancestor first.
+func themeChain(manifests map[string]*ThemeManifest, theme string) ([]string, error) {
+	var chain []string
+	for name := theme; name != ""; name = manifests[name].Parent {
+		if _, ok := manifests[name]; !ok {
+			return nil, fmt.Errorf("parent theme %q does not exist", name)
+		}
+		for _, seen := range chain {
+			if seen == name {
+				return nil, fmt.Errorf("parent themes form a cycle: %s", strings.Join(append(chain, name), " -> "))
+			}
+		}
+		chain = append(chain, name)
+	}
+	// Reverse so children come last and override their parents.
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain, nil
+}
+
+// mergeManifests gives the manifest of the last theme in chain with
+// the params and asset bundles inherited from its ancestors.
+func mergeManifests(manifests map[string]*ThemeManifest, chain []string) *ThemeManifest {
+	merged := *manifests[chain[len(chain)-1]]
+	merged.Params = make(map[string]any)
+	merged.Assets = make(map[string][]string)
+	for _, name := range chain {
+		mergeParams(merged.Params, manifests[name].Params)
+		maps.Copy(merged.Assets, manifests[name].Assets)
+	}
+	return &merged
+}
+
+// mergeParams copies src into dst, merging nested maps so a child can
+// override a single nested param.
+func mergeParams(dst, src map[string]any) {
+	for key, value := range src {
+		if child, ok := value.(map[string]any); ok {
+			if parent, ok := dst[key].(map[string]any); ok {
+				nested := maps.Clone(parent)
+				mergeParams(nested, child)
+				dst[key] = nested
+				continue
+			}
+		}
+		dst[key] = value
+	}
+}
@@ -0,0 +1,166 @@
+package portfolio
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseThemeManifest(t *testing.T) {
+	manifest, err := ParseThemeManifest([]byte(joinLines(
+		"name: Child",
+		"version: 1.2.0-beta.1",
+		"parent: base",
+		"templates: [index, 404]",
+		"partials: [nav]",
+		"assets:",
+		"  css: [css/child.css]",
+		"params:",
+		"  prompt: $",
+		"  colors:",
+		"    accent: green",
+	)))
+	require.NoError(t, err)
+	assert.Equal(t, "base", manifest.Parent)
+	assert.Equal(t, []string{"index", "404"}, manifest.Templates)
+	assert.Equal(t, "green", manifest.Param("colors.accent"))
+	assert.Nil(t, manifest.Param("colors.missing"))
+	assert.Equal(t, []string{"/static/css/child.css"}, manifest.AssetURLs("css"))
+
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"missing name", "version: 1.0.0", "missing name"},
+		{"version", "name: T\nversion: one", `version "one" is not a semantic version`},
+		{"unknown field", "name: T\ntemplate: [index]", "field template not found"},
+		{"parent path", "name: T\nparent: ../other", `parent "../other" is not a theme directory name`},
+		{"template extension", "name: T\ntemplates: [index.html]", `template "index.html" should be a file name without .html`},
+		{"partial directory", "name: T\npartials: [a/b]", `partial "a/b" should be a file name without .html`},
+		{"asset path", "name: T\nassets:\n  css: [/etc/passwd]", `asset "/etc/passwd" in bundle css is not a path within static`},
+		{"param name", "name: T\nparams:\n  \"two words\": x", `param "two words" is not a valid name`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseThemeManifest([]byte(tt.src))
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func inheritedThemeFixture() fstest.MapFS {
+	return fstest.MapFS{
+		"themes/base/theme.yaml": {Data: []byte(joinLines(
+			"name: Base", "version: 1.0.0",
+			"templates: [index, single]", "partials: [nav]",
+			"assets:", "  css: [css/base.css]", "  js: [js/app.js]",
+			"params:", "  prompt: base$", "  colors:", "    fg: green", "    bg: black",
+		))},
+		"themes/base/templates/baseof.html": {Data: []byte(`<title>{{ themeParam "prompt" }}</title>{{ range themeAssets "css" }}<link href="{{ . }}">{{ end }}{{ template "partials/nav" . }}{{ template "main" . }}`)},
+		"themes/base/templates/index.html":  {Data: []byte(`{{ define "main" }}base index {{ themeParam "colors.fg" }}/{{ themeParam "colors.bg" }}{{ end }}`)},
+		"themes/base/templates/single.html": {Data: []byte(`{{ define "main" }}base single{{ end }}`)},
+		"themes/base/partials/nav.html":     {Data: []byte(`<nav>base</nav>`)},
+		"themes/base/static/css/base.css":   {Data: []byte("base")},
+		"themes/base/static/js/app.js":      {Data: []byte("app")},
+
+		"themes/child/theme.yaml": {Data: []byte(joinLines(
+			"name: Child", "parent: base",
+			"assets:", "  css: [css/base.css, css/child.css]",
+			"params:", "  prompt: child$", "  colors:", "    fg: amber",
+		))},
+		"themes/child/templates/index.html": {Data: []byte(`{{ define "main" }}child index {{ themeParam "colors.fg" }}/{{ themeParam "colors.bg" }}{{ end }}`)},
+		"themes/child/partials/nav.html":    {Data: []byte(`<nav>child</nav>`)},
+		"themes/child/static/css/child.css": {Data: []byte("child")},
+
+		"themes/missing/theme.yaml": {Data: []byte("name: Missing\nparent: base\ntemplates: [archive]\nassets:\n  css: [css/gone.css]")},
+		"themes/orphan/theme.yaml":  {Data: []byte("name: Orphan\nparent: nowhere")},
+		"themes/loop-a/theme.yaml":  {Data: []byte("name: A\nparent: loop-b")},
+		"themes/loop-b/theme.yaml":  {Data: []byte("name: B\nparent: loop-a")},
+		"themes/bad/theme.yaml":     {Data: []byte("version: 2")},
+	}
+}
+
+func TestThemeInheritance(t *testing.T) {
+	themes, err := NewThemeManager(inheritedThemeFixture(), "base", nil, nil)
+	require.Error(t, err)
+	for _, want := range []string{
+		`theme missing: declared template "archive" does not exist`,
+		`theme missing: asset "css/gone.css" in bundle css does not exist`,
+		`theme orphan: parent theme "nowhere" does not exist`,
+		"parent themes form a cycle",
+		"theme bad: themes/bad/theme.yaml: missing name",
+	} {
+		assert.ErrorContains(t, err, want)
+	}
+	assert.Equal(t, []string{"base", "child"}, themes.Themes())
+
+	tests := []struct {
+		theme, name, want string
+	}{
+		{"base", "index", `<title>base$</title><link href="/static/css/base.css"><nav>base</nav>base index green/black`},
+		{"child", "index", `<title>child$</title><link href="/static/css/base.css"><link href="/static/css/child.css"><nav>child</nav>child index amber/black`},
+		{"child", "single", `<title>child$</title><link href="/static/css/base.css"><link href="/static/css/child.css"><nav>child</nav>base single`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.theme+"/"+tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, themes.Render(&buf, tt.theme, tt.name, nil))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+
+	manifest, ok := themes.Manifest("child")
+	require.True(t, ok)
+	assert.Equal(t, "Child", manifest.Name)
+	assert.Equal(t, []string{"/static/js/app.js"}, manifest.AssetURLs("js"))
+
+	for name, want := range map[string]string{
+		"css/child.css":    "themes/child/static/css/child.css",
+		"js/app.js":        "themes/base/static/js/app.js",
+		"../base/theme.js": "",
+	} {
+		p, _ := themes.StaticPath("child", name)
+		assert.Equal(t, want, p, name)
+	}
+}
+
+func TestThemeParamsOverride(t *testing.T) {
+	params := map[string]any{
+		"prompt": "site$",
+		"colors": map[string]any{"bg": "white"},
+	}
+	themes, _ := NewThemeManager(inheritedThemeFixture(), "base", params, nil)
+
+	// Site params override the defaults of the theme and its parents,
+	// nested params one at a time.
+	var buf bytes.Buffer
+	require.NoError(t, themes.Render(&buf, "child", "index", nil))
+	assert.Equal(t, `<title>site$</title><link href="/static/css/base.css"><link href="/static/css/child.css"><nav>child</nav>child index amber/white`, buf.String())
+
+	manifest, ok := themes.Manifest("base")
+	require.True(t, ok)
+	assert.Equal(t, "green", manifest.Param("colors.fg"))
+	assert.Equal(t, "white", manifest.Param("colors.bg"))
+}
+
+func TestDefaultThemeManifest(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), nil)
+	manifest, ok := server.themes.Manifest(DefaultTheme)
+	require.True(t, ok)
+	assert.Equal(t, "Green Nebula Terminal", manifest.Name)
+
+	req := httptest.NewRequest("GET", "/archive", nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<title>dev@portfolio:~$</title>")
+	assert.Contains(t, w.Body.String(), `<link rel="stylesheet" href="/static/css/stylesheet.css">`)
+	assert.Contains(t, w.Body.String(), `<script src="/static/js/starfield.js"></script>`)
+}
@@ -0,0 +1,285 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TimelineEventType identifies the source of a timeline event.
+type TimelineEventType string
+
+const (
+	TimelineExperience    TimelineEventType = "experience"
+	TimelineEducation     TimelineEventType = "education"
+	TimelineCertification TimelineEventType = "certification"
+	TimelinePost          TimelineEventType = "post"
+	TimelineProject       TimelineEventType = "project"
+)
+
+// TimelineEvent is a single normalized entry in the career timeline.
+type TimelineEvent struct {
+	Type     TimelineEventType `json:"type"`
+	Start    time.Time         `json:"start"`
+	End      time.Time         `json:"end,omitzero"`
+	Current  bool              `json:"current,omitempty"`
+	Title    string            `json:"title"`
+	Subtitle string            `json:"subtitle,omitempty"`
+	Link     string            `json:"link,omitempty"`
+}
+
+// Year reports whether the event overlaps the given year.
+func (e TimelineEvent) Year(year int) bool {
+	end := e.End
+	if end.IsZero() {
+		end = e.Start
+	}
+	return e.Start.Year() <= year && end.Year() >= year
+}
+
+// TimelineFilter narrows a timeline. Zero values match everything.
+type TimelineFilter struct {
+	Types []TimelineEventType
+	Year  int
+}
+
+// Match reports whether the event passes the filter.
+func (f TimelineFilter) Match(e TimelineEvent) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, e.Type) {
+		return false
+	}
+	if f.Year != 0 && !e.Year(f.Year) {
+		return false
+	}
+	return true
+}
+
+// Timeline is a chronologically sorted stream of events, newest first.
+type Timeline []TimelineEvent
+
+// NewTimeline merges résumé data and content pages into a timeline.
+// Pages without a date are skipped.
+func NewTimeline(data *Data, posts, projects []*Page) Timeline {
+	var events Timeline
+
+	if data != nil {
+		for _, exp := range data.Experience {
+			start, ok := exp.Start()
+			if !ok {
+				continue
+			}
+			var end time.Time
+			if !exp.Current {
+				end, _ = exp.End(time.Time{})
+			}
+			events = append(events, TimelineEvent{
+				Type:     TimelineExperience,
+				Start:    start,
+				End:      end,
+				Current:  exp.Current,
+				Title:    exp.Title,
+				Subtitle: exp.Company,
+				Link:     "/#" + exp.Anchor(),
+			})
+		}
+
+		for _, edu := range data.Education {
+			start, end, ok := parseYearRange(edu.Graduation)
+			if !ok {
+				continue
+			}
+			events = append(events, TimelineEvent{
+				Type:     TimelineEducation,
+				Start:    start,
+				End:      end,
+				Title:    edu.Degree,
+				Subtitle: edu.School,
+				Link:     "/#education",
+			})
+		}
+
+		for _, cert := range data.Certifications {
+			issued, ok := parseMonthYear(cert.Issued)
+			if !ok {
+				continue
+			}
+			expires, _ := parseMonthYear(cert.Expires)
+			events = append(events, TimelineEvent{
+				Type:     TimelineCertification,
+				Start:    issued,
+				End:      expires,
+				Title:    cert.Name,
+				Subtitle: cert.CredentialID,
+				Link:     "/#" + cert.Anchor(),
+			})
+		}
+	}
+
+	for _, page := range posts {
+		if event, ok := pageEvent(TimelinePost, page); ok {
+			events = append(events, event)
+		}
+	}
+	for _, page := range projects {
+		if event, ok := pageEvent(TimelineProject, page); ok {
+			events = append(events, event)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.After(events[j].Start)
+	})
+	return events
+}
+
+// Filter returns the events matching f.
+func (t Timeline) Filter(f TimelineFilter) Timeline {
+	filtered := Timeline{}
+	for _, e := range t {
+		if f.Match(e) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+// WriteICal writes the timeline as an iCalendar (RFC 5545) feed. Events
+// are written as all-day events; ongoing ones end at now.
+func (t Timeline) WriteICal(w io.Writer, baseURL string, now time.Time) error {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldICalLine(s))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//jlrickert.me//timeline//EN")
+	line("CALSCALE:GREGORIAN")
+	for _, e := range t {
+		// DTEND is exclusive. Current positions run until today.
+		end := e.End
+		switch {
+		case e.Current:
+			end = now
+		case end.IsZero():
+			end = e.Start
+		}
+		end = end.AddDate(0, 0, 1)
+
+		line("BEGIN:VEVENT")
+		line("UID:" + e.uid())
+		line("DTSTAMP:" + now.UTC().Format("20060102T150405Z"))
+		line("DTSTART;VALUE=DATE:" + e.Start.Format("20060102"))
+		line("DTEND;VALUE=DATE:" + end.Format("20060102"))
+		line("SUMMARY:" + escapeICal(e.Title))
+		if e.Subtitle != "" {
+			line("DESCRIPTION:" + escapeICal(e.Subtitle))
+		}
+		line("CATEGORIES:" + escapeICal(string(e.Type)))
+		if e.Link != "" {
+			line("URL:" + strings.TrimSuffix(baseURL, "/") + e.Link)
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// uid identifies the event in calendar feeds. It is derived from the
+// event itself, not its position, so it survives entries being added or
+// reordered. Experience and education include the company or school to
+// tell apart the same title held twice.
+func (e TimelineEvent) uid() string {
+	name := e.Title
+	if e.Type == TimelineExperience || e.Type == TimelineEducation {
+		name += " " + e.Subtitle
+	}
+	return fmt.Sprintf("%s-%s-%s@jlrickert.me", e.Type, e.Start.Format("20060102"), urlize(name))
+}
+
+// GetTimeline builds the timeline from data.yaml and the blog and
+// projects content sections.
+func (m *AssetManager) GetTimeline(ctx context.Context) (Timeline, error) {
+	data, err := m.GetData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := m.ListSection(ctx, "blog")
+	if err != nil {
+		return nil, err
+	}
+	projects, err := m.ListSection(ctx, "projects")
+	if err != nil {
+		return nil, err
+	}
+	return NewTimeline(data, posts, projects), nil
+}
+
+// pageEvent converts a dated content page to a timeline event.
+func pageEvent(kind TimelineEventType, page *Page) (TimelineEvent, bool) {
+	date := page.Front().Date
+	if date.IsZero() {
+		return TimelineEvent{}, false
+	}
+	return TimelineEvent{
+		Type:     kind,
+		Start:    date,
+		Title:    page.Title(),
+		Subtitle: page.Description(),
+		Link:     page.URL(),
+	}, true
+}
+
+// parseYearRange parses education dates such as "2014" or "2019-2020".
+func parseYearRange(value string) (time.Time, time.Time, bool) {
+	first, last, found := strings.Cut(strings.TrimSpace(value), "-")
+	start, ok := parseMonthYear(first)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	if !found {
+		return start, time.Time{}, true
+	}
+	end, ok := parseMonthYear(last)
+	if !ok {
+		return start, time.Time{}, true
+	}
+	return start, end, true
+}
+
+// escapeICal escapes TEXT values per RFC 5545 section 3.3.11.
+func escapeICal(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// foldICalLine folds content lines longer than 75 octets.
+func foldICalLine(s string) string {
+	if len(s) <= 75 {
+		return s
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
@@ -0,0 +1,142 @@
+package portfolio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const timelineYAML = `
+experience:
+  - title: Web Developer
+    company: Ecreative
+    start_date: April 2024
+    current: true
+  - title: Programmer
+    company: Deerwood Bank
+    start_date: June 2016
+    end_date: August 2016
+education:
+  - school: Metropolitan State University
+    degree: Computer Science
+    graduation: 2019-2020
+certifications:
+  - name: AWS Certified Developer - Associate
+    issued: November 2023
+    expires: November 2026
+`
+
+func testTimeline(t *testing.T) Timeline {
+	data, err := LoadData([]byte(timelineYAML))
+	require.NoError(t, err)
+
+	posts := []*Page{
+		{Path: "blog/first-post.md", Meta: map[string]any{
+			"title": "Welcome", "slug": "welcome", "date": "2025-11-17",
+		}},
+		{Path: "blog/undated.md", Meta: map[string]any{"title": "Undated"}},
+		{Path: "blog/empty-date.md", Meta: map[string]any{"title": "Empty", "date": ""}},
+		{Path: "blog/bad-date.md", Meta: map[string]any{"title": "Bad", "date": "someday"}},
+	}
+	projects := []*Page{
+		{Path: "projects/b2mfg/index.md", Meta: map[string]any{
+			"title": "B2MFG", "date": "2025-12-02",
+		}},
+	}
+	return NewTimeline(data, posts, projects)
+}
+
+func TestNewTimeline(t *testing.T) {
+	timeline := testTimeline(t)
+
+	require.Len(t, timeline, 6)
+	assert.Equal(t, "B2MFG", timeline[0].Title)
+	assert.Equal(t, "/projects/b2mfg/", timeline[0].Link)
+	assert.Equal(t, "/blog/welcome/", timeline[1].Link)
+	assert.Equal(t, TimelineExperience, timeline[2].Type)
+	assert.True(t, timeline[2].Current)
+	assert.Equal(t, "/#experience-ecreative-web-developer", timeline[2].Link)
+	assert.Equal(t, "/#certification-aws-certified-developer-associate", timeline[3].Link)
+	assert.Equal(t, "Programmer", timeline[len(timeline)-1].Title)
+
+	for i := 1; i < len(timeline); i++ {
+		assert.False(t, timeline[i].Start.After(timeline[i-1].Start))
+	}
+}
+
+func TestTimelineFilter(t *testing.T) {
+	timeline := testTimeline(t)
+
+	posts := timeline.Filter(TimelineFilter{Types: []TimelineEventType{TimelinePost}})
+	require.Len(t, posts, 1)
+	assert.Equal(t, "Welcome", posts[0].Title)
+
+	in2020 := timeline.Filter(TimelineFilter{Year: 2020})
+	require.Len(t, in2020, 1)
+	assert.Equal(t, TimelineEducation, in2020[0].Type)
+
+	in2025 := timeline.Filter(TimelineFilter{
+		Types: []TimelineEventType{TimelineCertification},
+		Year:  2025,
+	})
+	require.Len(t, in2025, 1)
+	assert.Equal(t, "AWS Certified Developer - Associate", in2025[0].Title)
+}
+
+func TestTimelineWriteICal(t *testing.T) {
+	timeline := testTimeline(t).Filter(TimelineFilter{
+		Types: []TimelineEventType{TimelinePost, TimelineCertification},
+	})
+
+	var buf bytes.Buffer
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, timeline.WriteICal(&buf, "https://jlrickert.me/", now))
+
+	ics := buf.String()
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(ics, "BEGIN:VEVENT"))
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20251117\r\n")
+	assert.Contains(t, ics, "URL:https://jlrickert.me/blog/welcome/\r\n")
+	assert.Contains(t, ics, "DTSTAMP:20260101T000000Z\r\n")
+
+	for line := range strings.SplitSeq(ics, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+	}
+}
+
+func TestTimelineWriteICalCurrent(t *testing.T) {
+	timeline := testTimeline(t).Filter(TimelineFilter{Types: []TimelineEventType{TimelineExperience}})
+
+	var buf bytes.Buffer
+	now := time.Date(2026, time.January, 15, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, timeline.WriteICal(&buf, "https://jlrickert.me/", now))
+
+	// The current position runs until today, the past one for its dates.
+	ics := buf.String()
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20240401\r\nDTEND;VALUE=DATE:20260116\r\n")
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20160601\r\nDTEND;VALUE=DATE:20160802\r\n")
+	assert.Contains(t, ics, "URL:https://jlrickert.me/#experience-ecreative-web-developer\r\n")
+}
+
+func TestTimelineICalUID(t *testing.T) {
+	start := time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)
+	job := TimelineEvent{Type: TimelineExperience, Start: start, Title: "Software Engineer", Subtitle: "Acme, Inc."}
+	assert.Equal(t, "experience-20200301-software-engineer-acme-inc@jlrickert.me", job.uid())
+
+	// The UID does not depend on the event's position in the feed.
+	other := TimelineEvent{Type: TimelinePost, Start: start, Title: "Hello"}
+	var first, second bytes.Buffer
+	require.NoError(t, Timeline{job, other}.WriteICal(&first, "", start))
+	require.NoError(t, Timeline{other, job}.WriteICal(&second, "", start))
+	assert.Contains(t, first.String(), "UID:experience-20200301-software-engineer-acme-inc@jlrickert.me\r\n")
+	assert.Contains(t, second.String(), "UID:experience-20200301-software-engineer-acme-inc@jlrickert.me\r\n")
+}
+
+func TestEscapeICal(t *testing.T) {
+	assert.Equal(t, `a\, b\; c\\d\ne`, escapeICal("a, b; c\\d\ne"))
+}
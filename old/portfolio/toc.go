@@ -0,0 +1,199 @@
+package portfolio
+
+import (
+	"bytes"
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// wordsPerMinute is the reading speed used for reading time estimates.
+const wordsPerMinute = 200
+
+// Table of contents levels, matching Hugo's default startLevel and
+// endLevel.
+const (
+	tocStartLevel = 2
+	tocEndLevel   = 3
+)
+
+// TOCEntry is a heading in a page's table of contents.
+type TOCEntry struct {
+	Level    int         `json:"level"`
+	ID       string      `json:"id"`
+	Title    string      `json:"title"`
+	Children []*TOCEntry `json:"children,omitempty"`
+}
+
+// pageStats is what is learned about a page's text while rendering it.
+type pageStats struct {
+	words    int
+	headings []TOCEntry
+}
+
+// WordCount returns the number of words in the page body, not counting
+// front matter or code blocks.
+func (p *Page) WordCount() int {
+	return p.analyze().words
+}
+
+// ReadingTime returns the estimated minutes needed to read the page.
+func (p *Page) ReadingTime() int {
+	words := p.WordCount()
+	if words == 0 {
+		return 0
+	}
+	return max(1, (words+wordsPerMinute-1)/wordsPerMinute)
+}
+
+// TableOfContents returns the h2 and h3 headings of the page nested by
+// level. IDs match the ones rendered by Markdown.
+func (p *Page) TableOfContents() []*TOCEntry {
+	var roots []*TOCEntry
+	var stack []*TOCEntry
+	for _, h := range p.analyze().headings {
+		if h.Level < tocStartLevel || h.Level > tocEndLevel {
+			continue
+		}
+		entry := &TOCEntry{Level: h.Level, ID: h.ID, Title: h.Title}
+		for len(stack) > 0 && stack[len(stack)-1].Level >= entry.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, entry)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, entry)
+		}
+		stack = append(stack, entry)
+	}
+	return roots
+}
+
+// TableOfContentsHTML renders the table of contents as nested lists in
+// a nav element, like Hugo's .TableOfContents.
+func (p *Page) TableOfContentsHTML() template.HTML {
+	entries := p.TableOfContents()
+	if len(entries) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	buf.WriteString(`<nav id="TableOfContents">`)
+	writeTOC(&buf, entries)
+	buf.WriteString(`</nav>`)
+	return template.HTML(buf.String())
+}
+
+func writeTOC(buf *bytes.Buffer, entries []*TOCEntry) {
+	buf.WriteString("<ul>")
+	for _, entry := range entries {
+		buf.WriteString(`<li><a href="#`)
+		buf.WriteString(html.EscapeString(entry.ID))
+		buf.WriteString(`">`)
+		buf.WriteString(html.EscapeString(entry.Title))
+		buf.WriteString("</a>")
+		if len(entry.Children) > 0 {
+			writeTOC(buf, entry.Children)
+		}
+		buf.WriteString("</li>")
+	}
+	buf.WriteString("</ul>")
+}
+
+// analyze returns the page stats, deriving them from the rendered HTML
+// for pages that were not loaded from markdown.
+func (p *Page) analyze() *pageStats {
+	if p.stats == nil {
+		p.stats = htmlStats(p.Content)
+	}
+	return p.stats
+}
+
+// markdownStats walks a parsed markdown document. Code blocks and raw
+// HTML are not counted as words.
+func markdownStats(doc ast.Node, source []byte) *pageStats {
+	stats := &pageStats{}
+	var text bytes.Buffer
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			if n.Type() == ast.TypeBlock {
+				text.WriteByte(' ')
+			}
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.CodeBlock, *ast.FencedCodeBlock, *ast.HTMLBlock, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		case *ast.Heading:
+			id, _ := node.AttributeString("id")
+			idBytes, _ := id.([]byte)
+			stats.headings = append(stats.headings, TOCEntry{
+				Level: node.Level,
+				ID:    string(idBytes),
+				Title: nodeText(node, source),
+			})
+		case *ast.Text:
+			text.Write(node.Segment.Value(source))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				text.WriteByte(' ')
+			}
+		case *ast.String:
+			text.Write(node.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	stats.words = len(strings.Fields(text.String()))
+	return stats
+}
+
+var (
+	htmlTag     = regexp.MustCompile(`<[^>]*>`)
+	htmlPre     = regexp.MustCompile(`(?is)<pre[\s>].*?</pre>`)
+	htmlHeading = regexp.MustCompile(`(?is)<h([1-6])([^>]*)>(.*?)</h[1-6]>`)
+)
+
+// htmlStats derives page stats from rendered HTML.
+func htmlStats(content []byte) *pageStats {
+	stats := &pageStats{}
+	stats.words = len(strings.Fields(plainText(htmlPre.ReplaceAll(content, nil))))
+	for _, m := range htmlHeading.FindAllSubmatch(content, -1) {
+		entry := TOCEntry{
+			Level: int(m[1][0] - '0'),
+			Title: strings.TrimSpace(plainText(m[3])),
+		}
+		if id := htmlID.FindSubmatch(m[2]); id != nil {
+			entry.ID = string(id[1])
+		}
+		stats.headings = append(stats.headings, entry)
+	}
+	return stats
+}
+
+// nodeText returns the plain text of an inline tree.
+func nodeText(n ast.Node, source []byte) string {
+	var buf bytes.Buffer
+	ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := c.(type) {
+		case *ast.Text:
+			buf.Write(t.Segment.Value(source))
+			if t.SoftLineBreak() {
+				buf.WriteByte(' ')
+			}
+		case *ast.String:
+			buf.Write(t.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(buf.String())
+}
+
+// plainText strips tags from rendered HTML.
+func plainText(content []byte) string {
+	return html.UnescapeString(htmlTag.ReplaceAllString(string(content), " "))
+}
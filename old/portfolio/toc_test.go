@@ -0,0 +1,106 @@
+package portfolio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageWordCount(t *testing.T) {
+	page := testPage("blog/words.md", joinLines(
+		"---",
+		"title: Words In The Title Do Not Count",
+		"tags: [go]",
+		"---",
+		"# Heading words",
+		"",
+		"One *two* three [four five](/x/) `six`.",
+		"",
+		"```go",
+		"func ignored() { return }",
+		"```",
+		"",
+		"    indented code is ignored too",
+		"",
+		"<div>raw html ignored</div>",
+		"",
+		"- seven",
+		"- eight",
+	))
+
+	assert.Equal(t, 10, page.WordCount())
+	assert.Equal(t, 1, page.ReadingTime())
+}
+
+func TestPageReadingTime(t *testing.T) {
+	body := strings.Repeat("word ", 401)
+	page := testPage("blog/long.md", "---\ntitle: Long\n---\n"+body+"\n\n```\n"+body+"\n```\n")
+	assert.Equal(t, 401, page.WordCount())
+	assert.Equal(t, 3, page.ReadingTime())
+
+	empty := testPage("blog/empty.md", "---\ntitle: Empty\n---\n")
+	assert.Equal(t, 0, empty.ReadingTime())
+}
+
+func TestPageTableOfContents(t *testing.T) {
+	page := testPage("blog/toc.md", joinLines(
+		"---",
+		"title: TOC",
+		"---",
+		"# Title",
+		"## Getting Started",
+		"### Install `keg`",
+		"### Configure",
+		"#### Too deep",
+		"## Usage {#custom-id}",
+		"## Getting Started",
+	))
+
+	toc := page.TableOfContents()
+	require.Len(t, toc, 3)
+
+	assert.Equal(t, &TOCEntry{
+		Level: 2,
+		ID:    "getting-started",
+		Title: "Getting Started",
+		Children: []*TOCEntry{
+			{Level: 3, ID: "install-keg", Title: "Install keg"},
+			{Level: 3, ID: "configure", Title: "Configure"},
+		},
+	}, toc[0])
+	assert.Equal(t, "custom-id", toc[1].ID)
+	assert.Equal(t, "getting-started-1", toc[2].ID)
+
+	// Every TOC link points at a heading in the rendered content.
+	for _, id := range []string{"getting-started", "install-keg", "configure", "custom-id", "getting-started-1"} {
+		assert.Contains(t, string(page.Content), `id="`+id+`"`)
+	}
+
+	assert.Equal(t,
+		`<nav id="TableOfContents"><ul>`+
+			`<li><a href="#getting-started">Getting Started</a><ul>`+
+			`<li><a href="#install-keg">Install keg</a></li>`+
+			`<li><a href="#configure">Configure</a></li>`+
+			`</ul></li>`+
+			`<li><a href="#custom-id">Usage</a></li>`+
+			`<li><a href="#getting-started-1">Getting Started</a></li>`+
+			`</ul></nav>`,
+		string(page.TableOfContentsHTML()),
+	)
+}
+
+func TestPageStatsFromHTML(t *testing.T) {
+	page := &Page{Content: []byte(
+		`<h2 id="intro">Intro &amp; setup</h2><p>one two three</p><pre><code>skip me</code></pre><h3 id="more">More</h3>`,
+	)}
+
+	assert.Equal(t, 7, page.WordCount())
+	toc := page.TableOfContents()
+	require.Len(t, toc, 1)
+	assert.Equal(t, "Intro & setup", toc[0].Title)
+	assert.Equal(t, "more", toc[0].Children[0].ID)
+
+	assert.Empty(t, (&Page{}).TableOfContentsHTML())
+}
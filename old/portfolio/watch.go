@@ -0,0 +1,115 @@
+package portfolio
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Watcher polls a site directory and fires a callback when a file
+// under one of its roots is added, removed or modified, so edits are
+// served and searchable without an admin reload.
+type Watcher struct {
+	fsys     fs.FS
+	roots    []string
+	interval time.Duration
+	logger   *slog.Logger
+
+	mu        sync.Mutex
+	listeners []func()
+	last      uint64
+	done      chan struct{}
+}
+
+// NewWatcher creates a Watcher over roots of fsys, such as "content"
+// and "hugo.yaml", checked every interval.
+func NewWatcher(fsys fs.FS, interval time.Duration, logger *slog.Logger, roots ...string) *Watcher {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Watcher{fsys: fsys, roots: roots, interval: interval, logger: logger}
+}
+
+// OnChange registers fn to run after each detected change.
+func (w *Watcher) OnChange(fn func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.listeners = append(w.listeners, fn)
+}
+
+// Start records the current state and polls until Stop.
+func (w *Watcher) Start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done != nil {
+		return
+	}
+	w.last = w.fingerprint()
+	w.done = make(chan struct{})
+	go w.poll(w.done)
+}
+
+// Stop ends polling.
+func (w *Watcher) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done != nil {
+		close(w.done)
+		w.done = nil
+	}
+}
+
+func (w *Watcher) poll(done chan struct{}) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+// check fires the listeners when the roots differ from the last check.
+func (w *Watcher) check() {
+	sum := w.fingerprint()
+
+	w.mu.Lock()
+	if sum == w.last {
+		w.mu.Unlock()
+		return
+	}
+	w.last = sum
+	listeners := append([]func(){}, w.listeners...)
+	w.mu.Unlock()
+
+	w.logger.Info("site files changed")
+	for _, fn := range listeners {
+		fn()
+	}
+}
+
+// fingerprint hashes the path, size and modification time of every
+// file under the roots. Missing roots are skipped.
+func (w *Watcher) fingerprint() uint64 {
+	h := fnv.New64a()
+	for _, root := range w.roots {
+		fs.WalkDir(w.fsys, root, func(fp string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			fmt.Fprintf(h, "%s\x00%d\x00%d\n", fp, info.Size(), info.ModTime().UnixNano())
+			return nil
+		})
+	}
+	return h.Sum64()
+}
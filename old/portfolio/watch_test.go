@@ -0,0 +1,44 @@
+package portfolio
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatcherCheck(t *testing.T) {
+	fsys := fstest.MapFS{
+		"content/blog/post.md": {Data: []byte("one"), ModTime: time.Unix(1, 0)},
+		"static/logo.png":      {Data: []byte("png")},
+	}
+	watcher := NewWatcher(fsys, time.Hour, nil, "content", "hugo.yaml")
+	fired := 0
+	watcher.OnChange(func() { fired++ })
+	watcher.Start()
+	defer watcher.Stop()
+
+	watcher.check()
+	assert.Equal(t, 0, fired)
+
+	// Files outside the roots are ignored.
+	fsys["static/other.png"] = &fstest.MapFile{Data: []byte("png")}
+	watcher.check()
+	assert.Equal(t, 0, fired)
+
+	fsys["content/blog/post.md"] = &fstest.MapFile{Data: []byte("one"), ModTime: time.Unix(2, 0)}
+	watcher.check()
+	assert.Equal(t, 1, fired)
+
+	fsys["hugo.yaml"] = &fstest.MapFile{Data: []byte("title: Test\n")}
+	watcher.check()
+	assert.Equal(t, 2, fired)
+
+	delete(fsys, "content/blog/post.md")
+	watcher.check()
+	assert.Equal(t, 3, fired)
+
+	watcher.check()
+	assert.Equal(t, 3, fired)
+}